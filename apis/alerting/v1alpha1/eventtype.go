@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package v1alpha1
+
+// EventType values under the names they had before the enum was generated
+// from the schema.
+const (
+	// Deprecated: Use EventTypeEC2HighCPU.
+	EventTypeAWSCPUSaturation = EventTypeEC2HighCPU
+	// Deprecated: Use EventTypeOSIHighCPU.
+	EventTypeCPUSaturation = EventTypeOSIHighCPU
+	// Deprecated: Use EventTypeProcessNaHighConnFailRate.
+	EventTypeConnectivityProblem = EventTypeProcessNaHighConnFailRate
+	// Deprecated: Use EventTypeCustomAppCrashRateIncreased.
+	EventTypeCustomAppCrashRateIncrease = EventTypeCustomAppCrashRateIncreased
+	// Deprecated: Use EventTypeCustomApplicationErrorRateIncreased.
+	EventTypeCustomAppErrorRateIncrease = EventTypeCustomApplicationErrorRateIncreased
+	// Deprecated: Use EventTypeCustomApplicationSlowdown.
+	EventTypeCustomAppSlowdown = EventTypeCustomApplicationSlowdown
+	// Deprecated: Use EventTypeCustomApplicationUnexpectedLowLoad.
+	EventTypeCustomAppUnexpectedLowLoad = EventTypeCustomApplicationUnexpectedLowLoad
+	// Deprecated: Use EventTypeCustomApplicationUnexpectedHighLoad.
+	EventTypeCustomAppUnexpectedHighLoad = EventTypeCustomApplicationUnexpectedHighLoad
+	// Deprecated: Use EventTypeDCRUMSvcPerformanceDegradation.
+	EventTypeDataCenterServicePerformanceDegredation = EventTypeDCRUMSvcPerformanceDegradation
+	// Deprecated: Use EventTypeDCRUMSvcLowAvailability.
+	EventTypeDataCenterServiceUnvailable = EventTypeDCRUMSvcLowAvailability
+	// Deprecated: Use EventTypeESXiGuestCPULimitReached.
+	EventTypeESXiGuestCPUSaturation = EventTypeESXiGuestCPULimitReached
+	// Deprecated: Use EventTypeESXiGuestActiveSwapWait.
+	EventTypeESXiGuestMemorySaturation = EventTypeESXiGuestActiveSwapWait
+)
@@ -23,6 +23,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
 )
 
 // +kubebuilder:validation:Enum=PREDEFINED;CUSTOM
@@ -45,27 +47,6 @@ const (
 	SeverityLevelResource              SeverityLevel = "RESOURCE_CONTENTION"
 )
 
-// +kubebuilder:validation:Enum=EC2_HIGH_CPU;OSI_HIGH_CPU;ELB_HIGH_BACKEND_ERROR_RATE;PROCESS_NA_HIGH_CONN_FAIL_RATE;CUSTOM_APP_CRASH_RATE_INCREASED;CUSTOM_APPLICATION_ERROR_RATE_INCREASED;CUSTOM_APPLICATION_SLOWDOWN;CUSTOM_APPLICATION_UNEXPECTED_LOW_LOAD;CUSTOM_APPLICATION_UNEXPECTED_HIGH_LOAD;DCRUM_SVC_PERFORMANCE_DEGRADATION;DCRUM_SVC_LOW_AVAILABILITY;ESXI_GUEST_CPU_LIMIT_REACHED;ESXI_GUEST_ACTIVE_SWAP_WAIT;ESXI_HOST_CPU_SATURATION;ESXI_HOST_MEMORY_SATURATION
-type EventType string
-
-const (
-	EventTypeAWSCPUSaturation                        EventType = "EC2_HIGH_CPU"
-	EventTypeCPUSaturation                           EventType = "OSI_HIGH_CPU"
-	EventTypeELBHighBackendErrorRate                 EventType = "ELB_HIGH_BACKEND_ERROR_RATE"
-	EventTypeConnectivityProblem                     EventType = "PROCESS_NA_HIGH_CONN_FAIL_RATE"
-	EventTypeCustomAppCrashRateIncrease              EventType = "CUSTOM_APP_CRASH_RATE_INCREASED"
-	EventTypeCustomAppErrorRateIncrease              EventType = "CUSTOM_APPLICATION_ERROR_RATE_INCREASED"
-	EventTypeCustomAppSlowdown                       EventType = "CUSTOM_APPLICATION_SLOWDOWN"
-	EventTypeCustomAppUnexpectedLowLoad              EventType = "CUSTOM_APPLICATION_UNEXPECTED_LOW_LOAD"
-	EventTypeCustomAppUnexpectedHighLoad             EventType = "CUSTOM_APPLICATION_UNEXPECTED_HIGH_LOAD"
-	EventTypeDataCenterServicePerformanceDegredation EventType = "DCRUM_SVC_PERFORMANCE_DEGRADATION"
-	EventTypeDataCenterServiceUnvailable             EventType = "DCRUM_SVC_LOW_AVAILABILITY"
-	EventTypeESXiGuestCPUSaturation                  EventType = "ESXI_GUEST_CPU_LIMIT_REACHED"
-	EventTypeESXiGuestMemorySaturation               EventType = "ESXI_GUEST_ACTIVE_SWAP_WAIT"
-	EventTypeESXiHostCPUSaturation                   EventType = "ESXI_HOST_CPU_SATURATION"
-	EventTypeESXiHostMemorySaturation                EventType = "ESXI_HOST_MEMORY_SATURATION"
-)
-
 // +kubebuilder:validation:Enum=BEGINS_WITH;ENDS_WITH;CONTAINS;REGEX_MATCHES;STRING_EQUALS
 type Operator string
 
@@ -80,7 +61,9 @@ const (
 type MetadataFilterItem struct {
 	MetadataKey   string `json:"metadataKey"`   // GET /api/v2/eventProperties for list of available keys
 	MetadataValue string `json:"metadataValue"` // Value
-	Negate        bool   `json:"negate"`        // Negate the operator
+	// Deprecated: not supported by the alerting profile schema; ignored.
+	// +optional
+	Negate *bool `json:"negate,omitempty"`
 }
 
 type MetadataFilter struct {
@@ -131,6 +114,34 @@ type SeverityRule struct {
 	TagFilterIncludeMode TagFilterIncludeMode `json:"tagFilterIncludeMode"` // Possible values are `NONE`, `INCLUDE_ANY` and `INCLUDE_ALL`
 	// +optional
 	Tags []string `json:"tagFilter,omitempty"`
+	// TagRefs reference AutoTags whose tags are added to the tag filter. They
+	// are resolved to the current name of each AutoTag on every reconcile, and
+	// the Profile is not reconciled while any of them is not ready.
+	// +optional
+	TagRefs []TagReference `json:"tagFilterRefs,omitempty"`
+	// ResolvedTags are the tags TagRefs were last resolved to. They are set by
+	// the provider, which overwrites any values set by users.
+	// +optional
+	ResolvedTags []string `json:"resolvedTagFilter,omitempty"`
+}
+
+// A SeverityRuleObservation is the observed state of a severity rule.
+type SeverityRuleObservation struct {
+	SeverityLevel        SeverityLevel        `json:"severityLevel"`
+	DelayInMinutes       int32                `json:"delayInMinutes"`
+	TagFilterIncludeMode TagFilterIncludeMode `json:"tagFilterIncludeMode"`
+	// +optional
+	Tags []string `json:"tagFilter,omitempty"`
+}
+
+// A TagReference references an AutoTag whose tag a severity rule filters by.
+type TagReference struct {
+	// Name of the referenced AutoTag.
+	Name string `json:"name"`
+	// Value of the tag to filter by. Problems are filtered by the tag
+	// regardless of its value if it is not set.
+	// +optional
+	Value *string `json:"value,omitempty"`
 }
 
 // ProfileParameters are the configurable fields of a Profile.
@@ -148,12 +159,27 @@ type ProfileParameters struct {
 // ProfileObservation are the observable fields of a Profile.
 type ProfileObservation struct {
 	Id string `json:"id"`
+
+	apisv1alpha1.SettingsObjectObservation `json:",inline"`
+
+	// Name is the observed name of the profile.
+	Name string `json:"name,omitempty"`
+
+	// ManagementZone is the observed management zone of the profile.
+	ManagementZone *string `json:"managementZone,omitempty"`
+
+	// SeverityRules are the observed severity rules of the profile.
+	SeverityRules []SeverityRuleObservation `json:"severityRules,omitempty"`
+
+	// EventFilters are the observed event filters of the profile.
+	EventFilters []EventFilter `json:"eventFilters,omitempty"`
 }
 
 // A ProfileSpec defines the desired state of a Profile.
 type ProfileSpec struct {
-	xpv1.ResourceSpec `json:",inline"`
-	ForProvider       ProfileParameters `json:"forProvider"`
+	xpv1.ResourceSpec               `json:",inline"`
+	apisv1alpha1.SettingsObjectSpec `json:",inline"`
+	ForProvider                     ProfileParameters `json:"forProvider"`
 }
 
 // A ProfileStatus represents the observed state of a Profile.
@@ -188,6 +214,9 @@ type ProfileList struct {
 	Items           []Profile `json:"items"`
 }
 
+// Profiles are validated by the provider before they are admitted.
+// +kubebuilder:webhook:verbs=create;update,path=/validate-alerting-dynatrace-crossplane-io-v1alpha1-profile,mutating=false,failurePolicy=fail,groups=alerting.dynatrace.crossplane.io,resources=profiles,versions=v1alpha1,name=profiles.alerting.dynatrace.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
 // Profile type metadata.
 var (
 	ProfileKind             = reflect.TypeOf(Profile{}).Name()
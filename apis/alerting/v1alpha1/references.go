@@ -1,8 +1,23 @@
 package v1alpha1
 
 import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/reference"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tagsv1alpha1 "github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+)
+
+const (
+	errFmtGetAutoTag      = "cannot get referenced AutoTag %q"
+	errFmtAutoTagNotReady = "referenced AutoTag %q is not ready"
+	errFmtResolveTags     = "mg.Spec.ForProvider.SeverityRules[%d].TagRefs"
 )
 
 func ProfileID() reference.ExtractValueFn {
@@ -14,3 +29,46 @@ func ProfileID() reference.ExtractValueFn {
 		return r.Status.AtProvider.Id
 	}
 }
+
+// ResolveReferences of this Profile. The tag references of its severity rules
+// are resolved to the observed names of the referenced AutoTags on every
+// reconcile, so that renaming an AutoTag updates the tag filters. A Profile
+// that references an AutoTag that is not ready is unavailable.
+func (mg *Profile) ResolveReferences(ctx context.Context, c client.Reader) error {
+	for i := range mg.Spec.ForProvider.SeverityRules {
+		r := &mg.Spec.ForProvider.SeverityRules[i]
+		if len(r.TagRefs) == 0 {
+			r.ResolvedTags = nil
+			continue
+		}
+
+		tags := make([]string, len(r.TagRefs))
+		for j, ref := range r.TagRefs {
+			t, err := resolveTag(ctx, c, ref)
+			if err != nil {
+				mg.SetConditions(xpv1.Unavailable())
+				return errors.Wrapf(err, errFmtResolveTags, i)
+			}
+			tags[j] = t
+		}
+		r.ResolvedTags = tags
+	}
+	return nil
+}
+
+// resolveTag returns the tag of a tag filter that the supplied reference
+// resolves to: the observed name of the referenced AutoTag, followed by the
+// referenced value if any.
+func resolveTag(ctx context.Context, c client.Reader, ref TagReference) (string, error) {
+	t := &tagsv1alpha1.AutoTag{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, t); err != nil {
+		return "", errors.Wrapf(err, errFmtGetAutoTag, ref.Name)
+	}
+	if t.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue || t.Status.AtProvider.Name == "" {
+		return "", errors.Errorf(errFmtAutoTagNotReady, ref.Name)
+	}
+	if ref.Value == nil {
+		return t.Status.AtProvider.Name, nil
+	}
+	return t.Status.AtProvider.Name + ":" + *ref.Value, nil
+}
@@ -86,7 +86,9 @@ func (in *MetadataFilter) DeepCopyInto(out *MetadataFilter) {
 	if in.MetadataFilterItems != nil {
 		in, out := &in.MetadataFilterItems, &out.MetadataFilterItems
 		*out = make([]MetadataFilterItem, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -103,6 +105,11 @@ func (in *MetadataFilter) DeepCopy() *MetadataFilter {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetadataFilterItem) DeepCopyInto(out *MetadataFilterItem) {
 	*out = *in
+	if in.Negate != nil {
+		in, out := &in.Negate, &out.Negate
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataFilterItem.
@@ -192,6 +199,26 @@ func (in *ProfileList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProfileObservation) DeepCopyInto(out *ProfileObservation) {
 	*out = *in
+	in.SettingsObjectObservation.DeepCopyInto(&out.SettingsObjectObservation)
+	if in.ManagementZone != nil {
+		in, out := &in.ManagementZone, &out.ManagementZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.SeverityRules != nil {
+		in, out := &in.SeverityRules, &out.SeverityRules
+		*out = make([]SeverityRuleObservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EventFilters != nil {
+		in, out := &in.EventFilters, &out.EventFilters
+		*out = make([]EventFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileObservation.
@@ -242,6 +269,7 @@ func (in *ProfileParameters) DeepCopy() *ProfileParameters {
 func (in *ProfileSpec) DeepCopyInto(out *ProfileSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	out.SettingsObjectSpec = in.SettingsObjectSpec
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
@@ -259,7 +287,7 @@ func (in *ProfileSpec) DeepCopy() *ProfileSpec {
 func (in *ProfileStatus) DeepCopyInto(out *ProfileStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileStatus.
@@ -280,6 +308,18 @@ func (in *SeverityRule) DeepCopyInto(out *SeverityRule) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.TagRefs != nil {
+		in, out := &in.TagRefs, &out.TagRefs
+		*out = make([]TagReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResolvedTags != nil {
+		in, out := &in.ResolvedTags, &out.ResolvedTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeverityRule.
@@ -292,6 +332,46 @@ func (in *SeverityRule) DeepCopy() *SeverityRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeverityRuleObservation) DeepCopyInto(out *SeverityRuleObservation) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeverityRuleObservation.
+func (in *SeverityRuleObservation) DeepCopy() *SeverityRuleObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(SeverityRuleObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TagReference) DeepCopyInto(out *TagReference) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TagReference.
+func (in *TagReference) DeepCopy() *TagReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TagReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TextFilter) DeepCopyInto(out *TextFilter) {
 	*out = *in
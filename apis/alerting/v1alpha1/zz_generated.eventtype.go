@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by enumgen. DO NOT EDIT.
+
+package v1alpha1
+
+// EventType is an enum of the builtin:alerting.profile schema, version 8.3.
+// +kubebuilder:validation:Enum=EC2_HIGH_CPU;OSI_HIGH_CPU;ELB_HIGH_BACKEND_ERROR_RATE;PROCESS_NA_HIGH_CONN_FAIL_RATE;CUSTOM_APP_CRASH_RATE_INCREASED;CUSTOM_APPLICATION_ERROR_RATE_INCREASED;CUSTOM_APPLICATION_SLOWDOWN;CUSTOM_APPLICATION_UNEXPECTED_LOW_LOAD;CUSTOM_APPLICATION_UNEXPECTED_HIGH_LOAD;ESXI_GUEST_CPU_LIMIT_REACHED;ESXI_GUEST_ACTIVE_SWAP_WAIT;ESXI_HOST_CPU_SATURATION;ESXI_HOST_MEMORY_SATURATION;ESXI_VM_IMPACT_HOST_CPU_SATURATION;ESXI_VM_IMPACT_HOST_MEMORY_SATURATION;ESXI_HOST_NETWORK_PROBLEMS;ESXI_HOST_DISK_SLOW;EBS_VOLUME_HIGH_LATENCY;DATABASE_CONNECTION_FAILURE;SERVICE_ERROR_RATE_INCREASED;RDS_HIGH_LATENCY;OSI_NIC_UTILIZATION_HIGH;OSI_NIC_ERRORS_HIGH;OSI_NIC_DROPPED_PACKETS_HIGH;OSI_GRACEFULLY_SHUTDOWN;OSI_UNEXPECTEDLY_UNAVAILABLE;HOST_OF_SERVICE_UNAVAILABLE;ESXI_HOST_DISK_QUEUE_SLOW;APPLICATION_ERROR_RATE_INCREASED;AWS_LAMBDA_HIGH_ERROR_RATE;PROCESS_HIGH_GC_ACTIVITY;ESXI_HOST_DATASTORE_LOW_DISK_SPACE;OSI_LOW_DISK_SPACE;OSI_DISK_LOW_INODES;RDS_LOW_STORAGE_SPACE;PROCESS_MEMORY_RESOURCE_EXHAUSTED;OSI_HIGH_MEMORY;MOBILE_APP_CRASH_RATE_INCREASED;MOBILE_APPLICATION_ERROR_RATE_INCREASED;MOBILE_APPLICATION_SLOWDOWN;MOBILE_APPLICATION_UNEXPECTED_LOW_LOAD;MOBILE_APPLICATION_UNEXPECTED_HIGH_LOAD;MONITORING_UNAVAILABLE;PROCESS_NA_HIGH_LOSS_RATE;ESXI_HOST_OVERLOADED_STORAGE;PROCESS_CRASHED;PG_LOW_INSTANCE_COUNT;PGI_UNAVAILABLE;RDS_HIGH_CPU;RDS_LOW_MEMORY;RDS_OF_SERVICE_UNAVAILABLE;SERVICE_SLOWDOWN;RDS_RESTART_SEQUENCE;PGI_OF_SERVICE_UNAVAILABLE;OSI_SLOW_DISK;SYNTHETIC_NODE_OUTAGE;SYNTHETIC_PRIVATE_LOCATION_OUTAGE;PROCESS_THREADS_RESOURCE_EXHAUSTED;SERVICE_UNEXPECTED_HIGH_LOAD;APPLICATION_UNEXPECTED_HIGH_LOAD;SERVICE_UNEXPECTED_LOW_LOAD;APPLICATION_UNEXPECTED_LOW_LOAD;APPLICATION_SLOWDOWN;SYNTHETIC_GLOBAL_OUTAGE;SYNTHETIC_LOCAL_OUTAGE;SYNTHETIC_TEST_LOCATION_SLOWDOWN;HTTP_CHECK_GLOBAL_OUTAGE;HTTP_CHECK_LOCAL_OUTAGE;HTTP_CHECK_TEST_LOCATION_SLOWDOWN;EXTERNAL_SYNTHETIC_TEST_OUTAGE;EXTERNAL_SYNTHETIC_TEST_SLOWDOWN;DCRUM_SVC_PERFORMANCE_DEGRADATION;DCRUM_SVC_LOW_AVAILABILITY
+type EventType string
+
+// EventType values.
+const (
+	EventTypeEC2HighCPU                          EventType = "EC2_HIGH_CPU"                            // AWS CPU saturation
+	EventTypeOSIHighCPU                          EventType = "OSI_HIGH_CPU"                            // CPU saturation
+	EventTypeELBHighBackendErrorRate             EventType = "ELB_HIGH_BACKEND_ERROR_RATE"             // Classic Load Balancer has a high backend connection error rate
+	EventTypeProcessNaHighConnFailRate           EventType = "PROCESS_NA_HIGH_CONN_FAIL_RATE"          // Connectivity problem
+	EventTypeCustomAppCrashRateIncreased         EventType = "CUSTOM_APP_CRASH_RATE_INCREASED"         // Custom application crash rate increase
+	EventTypeCustomApplicationErrorRateIncreased EventType = "CUSTOM_APPLICATION_ERROR_RATE_INCREASED" // Custom application error rate increase
+	EventTypeCustomApplicationSlowdown           EventType = "CUSTOM_APPLICATION_SLOWDOWN"             // Custom application slow user actions
+	EventTypeCustomApplicationUnexpectedLowLoad  EventType = "CUSTOM_APPLICATION_UNEXPECTED_LOW_LOAD"  // Custom application unexpected drop in usage
+	EventTypeCustomApplicationUnexpectedHighLoad EventType = "CUSTOM_APPLICATION_UNEXPECTED_HIGH_LOAD" // Custom application unexpected high usage
+	EventTypeESXiGuestCPULimitReached            EventType = "ESXI_GUEST_CPU_LIMIT_REACHED"            // ESXi Guest CPU saturation
+	EventTypeESXiGuestActiveSwapWait             EventType = "ESXI_GUEST_ACTIVE_SWAP_WAIT"             // ESXi Guest memory saturation
+	EventTypeESXiHostCPUSaturation               EventType = "ESXI_HOST_CPU_SATURATION"                // ESXi Host CPU saturation
+	EventTypeESXiHostMemorySaturation            EventType = "ESXI_HOST_MEMORY_SATURATION"             // ESXi Host memory saturation
+	EventTypeESXiVMImpactHostCPUSaturation       EventType = "ESXI_VM_IMPACT_HOST_CPU_SATURATION"      // ESXi VM impact Host CPU saturation
+	EventTypeESXiVMImpactHostMemorySaturation    EventType = "ESXI_VM_IMPACT_HOST_MEMORY_SATURATION"   // ESXi VM impact Host memory saturation
+	EventTypeESXiHostNetworkProblems             EventType = "ESXI_HOST_NETWORK_PROBLEMS"              // ESXi network problems
+	EventTypeESXiHostDiskSlow                    EventType = "ESXI_HOST_DISK_SLOW"                     // ESXi slow disk
+	EventTypeEBSVolumeHighLatency                EventType = "EBS_VOLUME_HIGH_LATENCY"                 // Elastic Block Storage has high latency.
+	EventTypeDatabaseConnectionFailure           EventType = "DATABASE_CONNECTION_FAILURE"             // Failed database connects
+	EventTypeServiceErrorRateIncreased           EventType = "SERVICE_ERROR_RATE_INCREASED"            // Failure rate increase
+	EventTypeRDSHighLatency                      EventType = "RDS_HIGH_LATENCY"                        // High latency
+	EventTypeOSINICUtilizationHigh               EventType = "OSI_NIC_UTILIZATION_HIGH"                // High network utilization
+	EventTypeOSINICErrorsHigh                    EventType = "OSI_NIC_ERRORS_HIGH"                     // High number of network errors
+	EventTypeOSINICDroppedPacketsHigh            EventType = "OSI_NIC_DROPPED_PACKETS_HIGH"            // High rate of dropped packets
+	EventTypeOSIGracefullyShutdown               EventType = "OSI_GRACEFULLY_SHUTDOWN"                 // Host gracefully shutdown
+	EventTypeOSIUnexpectedlyUnavailable          EventType = "OSI_UNEXPECTEDLY_UNAVAILABLE"            // Host or monitoring unavailable
+	EventTypeHostOfServiceUnavailable            EventType = "HOST_OF_SERVICE_UNAVAILABLE"             // Host unavailable
+	EventTypeESXiHostDiskQueueSlow               EventType = "ESXI_HOST_DISK_QUEUE_SLOW"               // I/O commands queued
+	EventTypeApplicationErrorRateIncreased       EventType = "APPLICATION_ERROR_RATE_INCREASED"        // JavaScript error rate increase
+	EventTypeAWSLambdaHighErrorRate              EventType = "AWS_LAMBDA_HIGH_ERROR_RATE"              // Lambda high error rate
+	EventTypeProcessHighGCActivity               EventType = "PROCESS_HIGH_GC_ACTIVITY"                // Long garbage-collection time
+	EventTypeESXiHostDatastoreLowDiskSpace       EventType = "ESXI_HOST_DATASTORE_LOW_DISK_SPACE"      // Low datastore space
+	EventTypeOSILowDiskSpace                     EventType = "OSI_LOW_DISK_SPACE"                      // Low disk space
+	EventTypeOSIDiskLowInodes                    EventType = "OSI_DISK_LOW_INODES"                     // Low number of inodes available
+	EventTypeRDSLowStorageSpace                  EventType = "RDS_LOW_STORAGE_SPACE"                   // Low storage space
+	EventTypeProcessMemoryResourceExhausted      EventType = "PROCESS_MEMORY_RESOURCE_EXHAUSTED"       // Memory resources exhausted
+	EventTypeOSIHighMemory                       EventType = "OSI_HIGH_MEMORY"                         // Memory saturation
+	EventTypeMobileAppCrashRateIncreased         EventType = "MOBILE_APP_CRASH_RATE_INCREASED"         // Mobile app crash rate increase
+	EventTypeMobileApplicationErrorRateIncreased EventType = "MOBILE_APPLICATION_ERROR_RATE_INCREASED" // Mobile app error rate increase
+	EventTypeMobileApplicationSlowdown           EventType = "MOBILE_APPLICATION_SLOWDOWN"             // Mobile app slow user actions
+	EventTypeMobileApplicationUnexpectedLowLoad  EventType = "MOBILE_APPLICATION_UNEXPECTED_LOW_LOAD"  // Mobile app unexpected drop in usage
+	EventTypeMobileApplicationUnexpectedHighLoad EventType = "MOBILE_APPLICATION_UNEXPECTED_HIGH_LOAD" // Mobile app unexpected high usage
+	EventTypeMonitoringUnavailable               EventType = "MONITORING_UNAVAILABLE"                  // Monitoring unavailable
+	EventTypeProcessNaHighLossRate               EventType = "PROCESS_NA_HIGH_LOSS_RATE"               // Network problem
+	EventTypeESXiHostOverloadedStorage           EventType = "ESXI_HOST_OVERLOADED_STORAGE"            // Overloaded storage
+	EventTypeProcessCrashed                      EventType = "PROCESS_CRASHED"                         // Process crashed
+	EventTypePGLowInstanceCount                  EventType = "PG_LOW_INSTANCE_COUNT"                   // Process group low instance count
+	EventTypePGIUnavailable                      EventType = "PGI_UNAVAILABLE"                         // Process unavailable
+	EventTypeRDSHighCPU                          EventType = "RDS_HIGH_CPU"                            // RDS CPU saturation
+	EventTypeRDSLowMemory                        EventType = "RDS_LOW_MEMORY"                          // RDS memory saturation
+	EventTypeRDSOfServiceUnavailable             EventType = "RDS_OF_SERVICE_UNAVAILABLE"              // Relational database service unavailable
+	EventTypeServiceSlowdown                     EventType = "SERVICE_SLOWDOWN"                        // Response time degradation
+	EventTypeRDSRestartSequence                  EventType = "RDS_RESTART_SEQUENCE"                    // Restart sequence
+	EventTypePGIOfServiceUnavailable             EventType = "PGI_OF_SERVICE_UNAVAILABLE"              // Service process unavailable
+	EventTypeOSISlowDisk                         EventType = "OSI_SLOW_DISK"                           // Slow disk
+	EventTypeSyntheticNodeOutage                 EventType = "SYNTHETIC_NODE_OUTAGE"                   // Synthetic ActiveGate outage
+	EventTypeSyntheticPrivateLocationOutage      EventType = "SYNTHETIC_PRIVATE_LOCATION_OUTAGE"       // Synthetic private location outage
+	EventTypeProcessThreadsResourceExhausted     EventType = "PROCESS_THREADS_RESOURCE_EXHAUSTED"      // Threads resources exhausted
+	EventTypeServiceUnexpectedHighLoad           EventType = "SERVICE_UNEXPECTED_HIGH_LOAD"            // Unexpected high load
+	EventTypeApplicationUnexpectedHighLoad       EventType = "APPLICATION_UNEXPECTED_HIGH_LOAD"        // Unexpected high traffic
+	EventTypeServiceUnexpectedLowLoad            EventType = "SERVICE_UNEXPECTED_LOW_LOAD"             // Unexpected low load
+	EventTypeApplicationUnexpectedLowLoad        EventType = "APPLICATION_UNEXPECTED_LOW_LOAD"         // Unexpected low traffic
+	EventTypeApplicationSlowdown                 EventType = "APPLICATION_SLOWDOWN"                    // User action duration degradation
+	EventTypeSyntheticGlobalOutage               EventType = "SYNTHETIC_GLOBAL_OUTAGE"                 // Browser monitor global outage
+	EventTypeSyntheticLocalOutage                EventType = "SYNTHETIC_LOCAL_OUTAGE"                  // Browser monitor local outage
+	EventTypeSyntheticTestLocationSlowdown       EventType = "SYNTHETIC_TEST_LOCATION_SLOWDOWN"        // Browser monitor performance threshold violation
+	EventTypeHTTPCheckGlobalOutage               EventType = "HTTP_CHECK_GLOBAL_OUTAGE"                // HTTP monitor global outage
+	EventTypeHTTPCheckLocalOutage                EventType = "HTTP_CHECK_LOCAL_OUTAGE"                 // HTTP monitor local outage
+	EventTypeHTTPCheckTestLocationSlowdown       EventType = "HTTP_CHECK_TEST_LOCATION_SLOWDOWN"       // HTTP monitor performance threshold violation
+	EventTypeExternalSyntheticTestOutage         EventType = "EXTERNAL_SYNTHETIC_TEST_OUTAGE"          // Third party monitor outage
+	EventTypeExternalSyntheticTestSlowdown       EventType = "EXTERNAL_SYNTHETIC_TEST_SLOWDOWN"        // Third party monitor slowdown
+	EventTypeDCRUMSvcPerformanceDegradation      EventType = "DCRUM_SVC_PERFORMANCE_DEGRADATION"       // No longer offered by the schema.
+	EventTypeDCRUMSvcLowAvailability             EventType = "DCRUM_SVC_LOW_AVAILABILITY"              // No longer offered by the schema.
+)
+
+// EventTypes are all EventType values, in the order of the schema
+// followed by values it no longer offers.
+var EventTypes = []EventType{
+	EventTypeEC2HighCPU,
+	EventTypeOSIHighCPU,
+	EventTypeELBHighBackendErrorRate,
+	EventTypeProcessNaHighConnFailRate,
+	EventTypeCustomAppCrashRateIncreased,
+	EventTypeCustomApplicationErrorRateIncreased,
+	EventTypeCustomApplicationSlowdown,
+	EventTypeCustomApplicationUnexpectedLowLoad,
+	EventTypeCustomApplicationUnexpectedHighLoad,
+	EventTypeESXiGuestCPULimitReached,
+	EventTypeESXiGuestActiveSwapWait,
+	EventTypeESXiHostCPUSaturation,
+	EventTypeESXiHostMemorySaturation,
+	EventTypeESXiVMImpactHostCPUSaturation,
+	EventTypeESXiVMImpactHostMemorySaturation,
+	EventTypeESXiHostNetworkProblems,
+	EventTypeESXiHostDiskSlow,
+	EventTypeEBSVolumeHighLatency,
+	EventTypeDatabaseConnectionFailure,
+	EventTypeServiceErrorRateIncreased,
+	EventTypeRDSHighLatency,
+	EventTypeOSINICUtilizationHigh,
+	EventTypeOSINICErrorsHigh,
+	EventTypeOSINICDroppedPacketsHigh,
+	EventTypeOSIGracefullyShutdown,
+	EventTypeOSIUnexpectedlyUnavailable,
+	EventTypeHostOfServiceUnavailable,
+	EventTypeESXiHostDiskQueueSlow,
+	EventTypeApplicationErrorRateIncreased,
+	EventTypeAWSLambdaHighErrorRate,
+	EventTypeProcessHighGCActivity,
+	EventTypeESXiHostDatastoreLowDiskSpace,
+	EventTypeOSILowDiskSpace,
+	EventTypeOSIDiskLowInodes,
+	EventTypeRDSLowStorageSpace,
+	EventTypeProcessMemoryResourceExhausted,
+	EventTypeOSIHighMemory,
+	EventTypeMobileAppCrashRateIncreased,
+	EventTypeMobileApplicationErrorRateIncreased,
+	EventTypeMobileApplicationSlowdown,
+	EventTypeMobileApplicationUnexpectedLowLoad,
+	EventTypeMobileApplicationUnexpectedHighLoad,
+	EventTypeMonitoringUnavailable,
+	EventTypeProcessNaHighLossRate,
+	EventTypeESXiHostOverloadedStorage,
+	EventTypeProcessCrashed,
+	EventTypePGLowInstanceCount,
+	EventTypePGIUnavailable,
+	EventTypeRDSHighCPU,
+	EventTypeRDSLowMemory,
+	EventTypeRDSOfServiceUnavailable,
+	EventTypeServiceSlowdown,
+	EventTypeRDSRestartSequence,
+	EventTypePGIOfServiceUnavailable,
+	EventTypeOSISlowDisk,
+	EventTypeSyntheticNodeOutage,
+	EventTypeSyntheticPrivateLocationOutage,
+	EventTypeProcessThreadsResourceExhausted,
+	EventTypeServiceUnexpectedHighLoad,
+	EventTypeApplicationUnexpectedHighLoad,
+	EventTypeServiceUnexpectedLowLoad,
+	EventTypeApplicationUnexpectedLowLoad,
+	EventTypeApplicationSlowdown,
+	EventTypeSyntheticGlobalOutage,
+	EventTypeSyntheticLocalOutage,
+	EventTypeSyntheticTestLocationSlowdown,
+	EventTypeHTTPCheckGlobalOutage,
+	EventTypeHTTPCheckLocalOutage,
+	EventTypeHTTPCheckTestLocationSlowdown,
+	EventTypeExternalSyntheticTestOutage,
+	EventTypeExternalSyntheticTestSlowdown,
+	EventTypeDCRUMSvcPerformanceDegradation,
+	EventTypeDCRUMSvcLowAvailability,
+}
@@ -1,3 +1,4 @@
+//go:build generate
 // +build generate
 
 /*
@@ -19,11 +20,14 @@ limitations under the License.
 // NOTE: See the below link for details on what is happening here.
 // https://github.com/golang/go/wiki/Modules#how-can-i-track-tool-dependencies-for-a-module
 
-// Remove existing CRDs
-//go:generate rm -rf ../package/crds
+// Remove existing CRDs and webhook configurations
+//go:generate rm -rf ../package/crds ../package/webhookconfigurations
 
-// Generate deepcopy methodsets and CRD manifests
-//go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen object:headerFile=../hack/boilerplate.go.txt paths=./... crd:crdVersions=v1 output:artifacts:config=../package/crds
+// Generate enums from the upstream Settings 2.0 schemas
+//go:generate go run ../hack/enumgen -schema dynatrace/api/builtin/alerting/profile/schema.json -enum EventType -extra DCRUM_SVC_PERFORMANCE_DEGRADATION,DCRUM_SVC_LOW_AVAILABILITY -package v1alpha1 -header ../hack/boilerplate.go.txt -o alerting/v1alpha1/zz_generated.eventtype.go
+
+// Generate deepcopy methodsets, CRD manifests and webhook configurations
+//go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen object:headerFile=../hack/boilerplate.go.txt paths=./... crd:crdVersions=v1 webhook output:crd:artifacts:config=../package/crds output:webhook:artifacts:config=../package/webhookconfigurations
 
 // Generate crossplane-runtime methodsets (resource.Claim, etc)
 //go:generate go run -tags generate github.com/crossplane/crossplane-tools/cmd/angryjet generate-methodsets --header-file=../hack/boilerplate.go.txt ./...
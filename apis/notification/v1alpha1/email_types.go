@@ -23,6 +23,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
 )
 
 // EmailParameters are the configurable fields of a Email.
@@ -74,12 +76,42 @@ type EmailParameters struct {
 // EmailObservation are the observable fields of a Email.
 type EmailObservation struct {
 	ID string `json:"ID"`
+
+	apisv1alpha1.SettingsObjectObservation `json:",inline"`
+
+	// Enabled is whether the observed notification is enabled.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Name is the observed name of the notification.
+	Name string `json:"name,omitempty"`
+
+	// Subject is the observed subject of the notification.
+	Subject string `json:"subject,omitempty"`
+
+	// NotifyClosedProblems is whether an email is sent for closed problems.
+	NotifyClosedProblems bool `json:"notifyClosedProblems,omitempty"`
+
+	// Body is the observed content of the email.
+	Body string `json:"body,omitempty"`
+
+	// To are the observed primary recipients.
+	To []string `json:"to,omitempty"`
+
+	// Cc are the observed CC-recipients.
+	Cc []string `json:"cc,omitempty"`
+
+	// Bcc are the observed BCC-recipients.
+	Bcc []string `json:"bcc,omitempty"`
+
+	// AlertingProfile is the ID of the observed alerting profile.
+	AlertingProfile string `json:"alertingProfile,omitempty"`
 }
 
 // A EmailSpec defines the desired state of a Email.
 type EmailSpec struct {
-	xpv1.ResourceSpec `json:",inline"`
-	ForProvider       EmailParameters `json:"forProvider"`
+	xpv1.ResourceSpec               `json:",inline"`
+	apisv1alpha1.SettingsObjectSpec `json:",inline"`
+	ForProvider                     EmailParameters `json:"forProvider"`
 }
 
 // A EmailStatus represents the observed state of a Email.
@@ -114,6 +146,9 @@ type EmailList struct {
 	Items           []Email `json:"items"`
 }
 
+// Emails are validated by the provider before they are admitted.
+// +kubebuilder:webhook:verbs=create;update,path=/validate-notification-dynatrace-crossplane-io-v1alpha1-email,mutating=false,failurePolicy=fail,groups=notification.dynatrace.crossplane.io,resources=emails,versions=v1alpha1,name=emails.notification.dynatrace.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
 // Email type metadata.
 var (
 	EmailKind             = reflect.TypeOf(Email{}).Name()
@@ -23,6 +23,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
 )
 
 // SlackParameters are the configurable fields of a Slack.
@@ -65,12 +67,30 @@ type SlackParameters struct {
 type SlackObservation struct {
 	ID            string  `json:"id,omitempty"`
 	ObfuscatedUrl *string `json:"obfuscatedUrl,omitempty"`
+
+	apisv1alpha1.SettingsObjectObservation `json:",inline"`
+
+	// Enabled is whether the observed notification is enabled.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Name is the observed name of the notification.
+	Name string `json:"name,omitempty"`
+
+	// Channel is the observed channel the notification is posted to.
+	Channel string `json:"channel,omitempty"`
+
+	// Message is the observed content of the message.
+	Message string `json:"message,omitempty"`
+
+	// AlertingProfile is the ID of the observed alerting profile.
+	AlertingProfile string `json:"alertingProfile,omitempty"`
 }
 
 // A SlackSpec defines the desired state of a Slack.
 type SlackSpec struct {
-	xpv1.ResourceSpec `json:",inline"`
-	ForProvider       SlackParameters `json:"forProvider"`
+	xpv1.ResourceSpec               `json:",inline"`
+	apisv1alpha1.SettingsObjectSpec `json:",inline"`
+	ForProvider                     SlackParameters `json:"forProvider"`
 }
 
 // A SlackStatus represents the observed state of a Slack.
@@ -105,6 +125,9 @@ type SlackList struct {
 	Items           []Slack `json:"items"`
 }
 
+// Slacks are validated by the provider before they are admitted.
+// +kubebuilder:webhook:verbs=create;update,path=/validate-notification-dynatrace-crossplane-io-v1alpha1-slack,mutating=false,failurePolicy=fail,groups=notification.dynatrace.crossplane.io,resources=slacks,versions=v1alpha1,name=slacks.notification.dynatrace.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
 // Slack type metadata.
 var (
 	SlackKind             = reflect.TypeOf(Slack{}).Name()
@@ -88,6 +88,22 @@ func (in *EmailList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmailObservation) DeepCopyInto(out *EmailObservation) {
 	*out = *in
+	in.SettingsObjectObservation.DeepCopyInto(&out.SettingsObjectObservation)
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cc != nil {
+		in, out := &in.Cc, &out.Cc
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bcc != nil {
+		in, out := &in.Bcc, &out.Bcc
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailObservation.
@@ -149,6 +165,7 @@ func (in *EmailParameters) DeepCopy() *EmailParameters {
 func (in *EmailSpec) DeepCopyInto(out *EmailSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	out.SettingsObjectSpec = in.SettingsObjectSpec
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
@@ -166,7 +183,7 @@ func (in *EmailSpec) DeepCopy() *EmailSpec {
 func (in *EmailStatus) DeepCopyInto(out *EmailStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailStatus.
@@ -246,6 +263,7 @@ func (in *SlackObservation) DeepCopyInto(out *SlackObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	in.SettingsObjectObservation.DeepCopyInto(&out.SettingsObjectObservation)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackObservation.
@@ -292,6 +310,7 @@ func (in *SlackParameters) DeepCopy() *SlackParameters {
 func (in *SlackSpec) DeepCopyInto(out *SlackSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	out.SettingsObjectSpec = in.SettingsObjectSpec
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
@@ -23,6 +23,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
 )
 
 // AutoTagParameters are the configurable fields of a AutoTag.
@@ -116,12 +118,26 @@ type Condition struct {
 
 // AutoTagObservation are the observable fields of a AutoTag.
 type AutoTagObservation struct {
+	// ID is the ID of the observed settings object.
+	ID string `json:"id,omitempty"`
+
+	apisv1alpha1.SettingsObjectObservation `json:",inline"`
+
+	// Name is the observed name of the tag.
+	Name string `json:"name,omitempty"`
+
+	// Description is the observed description of the tag.
+	Description *string `json:"description,omitempty"`
+
+	// Rules are the observed rules of the tag.
+	Rules []Rule `json:"rules,omitempty"`
 }
 
 // A AutoTagSpec defines the desired state of a AutoTag.
 type AutoTagSpec struct {
-	xpv1.ResourceSpec `json:",inline"`
-	ForProvider       AutoTagParameters `json:"forProvider"`
+	xpv1.ResourceSpec               `json:",inline"`
+	apisv1alpha1.SettingsObjectSpec `json:",inline"`
+	ForProvider                     AutoTagParameters `json:"forProvider"`
 }
 
 // A AutoTagStatus represents the observed state of a AutoTag.
@@ -156,6 +172,9 @@ type AutoTagList struct {
 	Items           []AutoTag `json:"items"`
 }
 
+// AutoTags are validated by the provider before they are admitted.
+// +kubebuilder:webhook:verbs=create;update,path=/validate-tags-dynatrace-crossplane-io-v1alpha1-autotag,mutating=false,failurePolicy=fail,groups=tags.dynatrace.crossplane.io,resources=autotags,versions=v1alpha1,name=autotags.tags.dynatrace.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
 // AutoTag type metadata.
 var (
 	AutoTagKind             = reflect.TypeOf(AutoTag{}).Name()
@@ -87,6 +87,19 @@ func (in *AutoTagList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoTagObservation) DeepCopyInto(out *AutoTagObservation) {
 	*out = *in
+	in.SettingsObjectObservation.DeepCopyInto(&out.SettingsObjectObservation)
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]Rule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoTagObservation.
@@ -130,6 +143,7 @@ func (in *AutoTagParameters) DeepCopy() *AutoTagParameters {
 func (in *AutoTagSpec) DeepCopyInto(out *AutoTagSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	out.SettingsObjectSpec = in.SettingsObjectSpec
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
@@ -147,7 +161,7 @@ func (in *AutoTagSpec) DeepCopy() *AutoTagSpec {
 func (in *AutoTagStatus) DeepCopyInto(out *AutoTagStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoTagStatus.
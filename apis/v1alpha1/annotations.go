@@ -0,0 +1,36 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Annotations understood by all Dynatrace managed resources.
+const (
+	// AnnotationKeyImportByName opts a managed resource into adopting an
+	// existing settings object whose name matches spec.forProvider.name
+	// instead of creating a new one. It only applies while the resource has
+	// no external name of its own.
+	AnnotationKeyImportByName = Group + "/import-by-name"
+
+	// AnnotationKeyIgnoreDrift is a comma separated list of settings object
+	// property paths whose differences from the desired state are not
+	// considered drift, e.g. "emailNotification.ccRecipients,rules.enabled".
+	AnnotationKeyIgnoreDrift = Group + "/ignore-drift"
+
+	// AnnotationKeyForceDelete deletes the settings object of a managed
+	// resource when it is set to "true", even if other settings objects in
+	// the tenant still reference it.
+	AnnotationKeyForceDelete = Group + "/force-delete"
+)
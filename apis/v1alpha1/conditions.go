@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// TypeAPIRequest indicates whether the last request made to the Dynatrace
+// Settings API on behalf of a managed resource succeeded.
+const TypeAPIRequest xpv1.ConditionType = "APIRequest"
+
+// TypeValidated indicates whether the API accepted the desired state of a
+// managed resource when it was last validated.
+const TypeValidated xpv1.ConditionType = "Validated"
+
+// TypeDeletionBlocked indicates whether the deletion of the settings object of
+// a managed resource is blocked by other settings objects that reference it.
+const TypeDeletionBlocked xpv1.ConditionType = "DeletionBlocked"
+
+// TypeObserved indicates whether the observed settings object of a managed
+// resource is fully recorded in its status.
+const TypeObserved xpv1.ConditionType = "Observed"
+
+// Reasons an APIRequest condition may have. A Validated condition is either
+// Valid or InvalidSpec.
+const (
+	// ReasonRequestSucceeded means the last request succeeded.
+	ReasonRequestSucceeded xpv1.ConditionReason = "Succeeded"
+
+	// ReasonValid means the API accepted the desired state.
+	ReasonValid xpv1.ConditionReason = "Valid"
+
+	// ReasonInvalidSpec means the API rejected the desired state as invalid.
+	// The request is not retried until the spec changes.
+	ReasonInvalidSpec xpv1.ConditionReason = "InvalidSpec"
+
+	// ReasonUnauthorized means the API token is invalid or expired.
+	ReasonUnauthorized xpv1.ConditionReason = "Unauthorized"
+
+	// ReasonForbidden means the API token lacks a required scope.
+	ReasonForbidden xpv1.ConditionReason = "Forbidden"
+
+	// ReasonThrottled means the API rate limit was exceeded.
+	ReasonThrottled xpv1.ConditionReason = "Throttled"
+
+	// ReasonServerError means the API failed to process the request.
+	ReasonServerError xpv1.ConditionReason = "ServerError"
+)
+
+// Reasons an Observed condition may have.
+const (
+	// ReasonComplete means the observed settings object is fully recorded.
+	ReasonComplete xpv1.ConditionReason = "Complete"
+
+	// ReasonUnsupportedValues means parts of the observed settings object
+	// use values the provider does not support, e.g. values Dynatrace added
+	// after the provider was released, and are not recorded.
+	ReasonUnsupportedValues xpv1.ConditionReason = "UnsupportedValues"
+)
+
+// Reasons a DeletionBlocked condition may have.
+const (
+	// ReasonHasDependents means other settings objects reference the
+	// settings object.
+	ReasonHasDependents xpv1.ConditionReason = "HasDependents"
+
+	// ReasonNoDependents means no other settings object references the
+	// settings object.
+	ReasonNoDependents xpv1.ConditionReason = "NoDependents"
+
+	// ReasonForced means the settings object is deleted regardless of its
+	// dependents.
+	ReasonForced xpv1.ConditionReason = "Forced"
+)
+
+// APIRequestSucceeded returns a condition indicating that the last request
+// made to the Settings API succeeded.
+func APIRequestSucceeded() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAPIRequest,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRequestSucceeded,
+	}
+}
+
+// APIRequestFailed returns a condition indicating that the last request made
+// to the Settings API failed for the supplied reason.
+func APIRequestFailed(reason xpv1.ConditionReason, message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAPIRequest,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// Valid returns a condition indicating that the API accepted the desired
+// state.
+func Valid() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeValidated,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonValid,
+	}
+}
+
+// Invalid returns a condition indicating that the API rejected the desired
+// state, listing the constraint violations in its message.
+func Invalid(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeValidated,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonInvalidSpec,
+		Message:            message,
+	}
+}
+
+// ObservedCompletely returns a condition indicating that the observed settings
+// object is fully recorded.
+func ObservedCompletely() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeObserved,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonComplete,
+	}
+}
+
+// ObservedPartially returns a condition indicating that the parts of the
+// observed settings object listed in the message use unsupported values and
+// are not recorded.
+func ObservedPartially(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeObserved,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnsupportedValues,
+		Message:            message,
+	}
+}
+
+// DeletionBlocked returns a condition indicating that the settings object is
+// not deleted because the settings objects listed in the message reference
+// it.
+func DeletionBlocked(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionBlocked,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonHasDependents,
+		Message:            message,
+	}
+}
+
+// DeletionAllowed returns a condition indicating that the settings object may
+// be deleted for the supplied reason.
+func DeletionAllowed(reason xpv1.ConditionReason) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionBlocked,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SettingsObjectSpec configures how the desired state of a Settings 2.0
+// object is applied.
+type SettingsObjectSpec struct {
+	// ValidateOnly only validates the desired state with the API, e.g. in
+	// preview environments. The settings object is never created, updated or
+	// deleted; the outcome is reported in the Validated condition.
+	// +optional
+	ValidateOnly bool `json:"validateOnly,omitempty"`
+}
+
+// SettingsObjectObservation is the observed metadata of a Settings 2.0
+// object.
+type SettingsObjectObservation struct {
+	// SchemaID is the ID of the schema the settings object belongs to.
+	SchemaID string `json:"schemaId,omitempty"`
+
+	// SchemaVersion is the version of the schema the settings object was
+	// last written with.
+	SchemaVersion string `json:"schemaVersion,omitempty"`
+
+	// Scope is the scope the settings object applies to.
+	Scope string `json:"scope,omitempty"`
+
+	// CreatedAt is the time the settings object was created.
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// CreatedBy is the user or token that created the settings object.
+	CreatedBy string `json:"createdBy,omitempty"`
+
+	// ModifiedAt is the time the settings object was last modified.
+	ModifiedAt *metav1.Time `json:"modifiedAt,omitempty"`
+
+	// ModifiedBy is the user or token that last modified the settings
+	// object.
+	ModifiedBy string `json:"modifiedBy,omitempty"`
+
+	// UpdateToken identifies the observed version of the settings object.
+	// It is sent with every update so that changes made in the tenant since
+	// the last observation are never overwritten unseen.
+	UpdateToken string `json:"updateToken,omitempty"`
+
+	// Drift lists the paths of the settings object properties that differed
+	// from the desired state when the object was last observed.
+	Drift []string `json:"drift,omitempty"`
+
+	// RejectedGeneration is the generation of the managed resource whose
+	// desired state the API last rejected as invalid. That generation is not
+	// sent to the API again.
+	RejectedGeneration int64 `json:"rejectedGeneration,omitempty"`
+}
@@ -190,6 +190,49 @@ func (in *ProviderCredentials) DeepCopy() *ProviderCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SettingsObjectObservation) DeepCopyInto(out *SettingsObjectObservation) {
+	*out = *in
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ModifiedAt != nil {
+		in, out := &in.ModifiedAt, &out.ModifiedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingsObjectObservation.
+func (in *SettingsObjectObservation) DeepCopy() *SettingsObjectObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(SettingsObjectObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SettingsObjectSpec) DeepCopyInto(out *SettingsObjectSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingsObjectSpec.
+func (in *SettingsObjectSpec) DeepCopy() *SettingsObjectSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SettingsObjectSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoreConfig) DeepCopyInto(out *StoreConfig) {
 	*out = *in
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The export command writes manifests of managed resources that desire the
+// settings objects of a Dynatrace tenant.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	dynatrace "github.com/crossplane/provider-dynatrace/internal/controller"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+	"github.com/crossplane/provider-dynatrace/internal/export"
+)
+
+func main() {
+	var (
+		app            = kingpin.New(filepath.Base(os.Args[0]), "Export the settings objects of a Dynatrace tenant as Crossplane managed resources.").DefaultEnvars()
+		credentials    = app.Arg("credentials", "File containing the credentials of the tenant, in the format of the ProviderConfig credentials secret.").Required().ExistingFile()
+		providerConfig = app.Flag("provider-config", "Name of the ProviderConfig the exported managed resources reference.").Default("default").String()
+		kinds          = app.Flag("kind", "GroupKind to export, e.g. Profile.alerting.dynatrace.crossplane.io. May be repeated. All kinds are exported if not set.").Strings()
+		output         = app.Flag("output", "File to write the manifests to. They are written to stdout if not set.").Short('o').String()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	creds, err := os.ReadFile(*credentials)
+	kingpin.FatalIfError(err, "Cannot read credentials")
+
+	exporters, err := selectKinds(dynatrace.Exporters(), *kinds)
+	kingpin.FatalIfError(err, "Cannot select kinds")
+
+	exported, err := export.Export(creds, exporters, *providerConfig)
+	kingpin.FatalIfError(err, "Cannot export settings objects")
+
+	mgs := make([]resource.Managed, len(exported))
+	for i, x := range exported {
+		mgs[i] = x.Managed
+		if w := x.Warning(); w != "" {
+			fmt.Fprintln(os.Stderr, "warning:", w)
+		}
+	}
+
+	w := os.Stdout
+	if *output != "" {
+		w, err = os.Create(*output)
+		kingpin.FatalIfError(err, "Cannot create output file")
+	}
+	kingpin.FatalIfError(export.Write(w, mgs), "Cannot write manifests")
+	kingpin.FatalIfError(w.Close(), "Cannot close output")
+}
+
+// selectKinds returns the exporters of the supplied GroupKinds, in the order
+// of all, or all exporters if none are supplied.
+func selectKinds(all []generic.Exporter, kinds []string) ([]generic.Exporter, error) {
+	if len(kinds) == 0 {
+		return all, nil
+	}
+
+	want := map[string]bool{}
+	for _, k := range kinds {
+		want[k] = true
+	}
+
+	var out []generic.Exporter
+	for _, e := range all {
+		if want[e.GroupKind()] {
+			out = append(out, e)
+			delete(want, e.GroupKind())
+		}
+	}
+	for k := range want {
+		return nil, errors.Errorf("unknown kind %q", k)
+	}
+	return out, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The plan command previews the changes the provider would make to the
+// settings objects of a Dynatrace tenant to reconcile a set of manifests.
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/provider-dynatrace/apis"
+	dynatrace "github.com/crossplane/provider-dynatrace/internal/controller"
+	"github.com/crossplane/provider-dynatrace/internal/plan"
+)
+
+func main() {
+	var (
+		app         = kingpin.New(filepath.Base(os.Args[0]), "Preview the changes the Dynatrace provider would make to a tenant, without making them.").DefaultEnvars()
+		credentials = app.Flag("credentials", "File containing the credentials of the tenant, in the format of the ProviderConfig credentials secret.").Required().ExistingFile()
+		deleted     = app.Flag("deleted", "Manifest file of managed resources that would be deleted, e.g. because they were removed from the repository. May be repeated.").ExistingFiles()
+		manifests   = app.Arg("manifests", "Manifest files of the desired managed resources. Other objects are ignored.").Required().ExistingFiles()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	creds, err := os.ReadFile(*credentials)
+	kingpin.FatalIfError(err, "Cannot read credentials")
+
+	s := runtime.NewScheme()
+	kingpin.FatalIfError(apis.AddToScheme(s), "Cannot add Dynatrace APIs to scheme")
+
+	desired, err := readAll(s, *manifests)
+	kingpin.FatalIfError(err, "Cannot read manifests")
+	del, err := readAll(s, *deleted)
+	kingpin.FatalIfError(err, "Cannot read deleted manifests")
+
+	rs, err := plan.Plan(context.Background(), s, creds, dynatrace.Planners(), desired, del)
+	kingpin.FatalIfError(err, "Cannot plan changes")
+	kingpin.FatalIfError(plan.Write(os.Stdout, rs), "Cannot write plan")
+
+	if plan.Summarize(rs).Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// readAll reads the managed resources of the supplied files.
+func readAll(s *runtime.Scheme, files []string) ([]resource.Managed, error) {
+	var out []resource.Managed
+	for _, name := range files {
+		f, err := os.Open(filepath.Clean(name))
+		if err != nil {
+			return nil, err
+		}
+		mgs, err := plan.Read(f, s)
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, mgs...)
+	}
+	return out, nil
+}
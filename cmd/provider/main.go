@@ -28,6 +28,7 @@ import (
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
@@ -40,6 +41,7 @@ import (
 	"github.com/crossplane/provider-dynatrace/apis/v1alpha1"
 	dynatrace "github.com/crossplane/provider-dynatrace/internal/controller"
 	"github.com/crossplane/provider-dynatrace/internal/features"
+	"github.com/crossplane/provider-dynatrace/internal/tracing"
 )
 
 func main() {
@@ -55,6 +57,14 @@ func main() {
 		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
 		enableManagementPolicies   = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("false").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
+
+		enableControllers = app.Flag("enable-controllers", "Patterns of the kinds whose controllers are started, e.g. '*.alerting' or 'Email.notification'. May be repeated or comma-separated. Kinds whose CRD is not installed are skipped.").Default("*").Envar("ENABLE_CONTROLLERS").Strings()
+
+		webhookTLSCertDir = app.Flag("webhook-tls-cert-dir", "The directory of the TLS certificate used by the webhook server. It must contain tls.crt and tls.key files. Webhooks are disabled if not set.").Envar("WEBHOOK_TLS_CERT_DIR").String()
+
+		otlpEndpoint     = app.Flag("otlp-endpoint", "The host:port of the OTLP/HTTP collector traces of reconciles and Dynatrace API calls are exported to. Tracing is disabled if not set.").Envar("OTLP_ENDPOINT").String()
+		otlpInsecure     = app.Flag("otlp-insecure", "Export traces over HTTP rather than HTTPS.").Default("false").Envar("OTLP_INSECURE").Bool()
+		traceSampleRatio = app.Flag("trace-sample-ratio", "The fraction of reconciles that are traced.").Default("1").Envar("TRACE_SAMPLE_RATIO").Float64()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -67,6 +77,12 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{Endpoint: *otlpEndpoint, Insecure: *otlpInsecure, SampleRatio: *traceSampleRatio})
+	kingpin.FatalIfError(err, "Cannot setup tracing")
+	if *otlpEndpoint != "" {
+		log.Info("Exporting traces", "endpoint", *otlpEndpoint, "sample-ratio", *traceSampleRatio)
+	}
+
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
@@ -85,6 +101,8 @@ func main() {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
+
+		WebhookServer: webhook.NewServer(webhook.Options{CertDir: *webhookTLSCertDir}),
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Dynatrace APIs to scheme")
@@ -121,6 +139,15 @@ func main() {
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaManagementPolicies)
 	}
 
-	kingpin.FatalIfError(dynatrace.Setup(mgr, o), "Cannot setup Dynatrace controllers")
-	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
+	selected, err := dynatrace.NewSelector(*enableControllers...)
+	kingpin.FatalIfError(err, "Cannot parse enabled controllers")
+	kingpin.FatalIfError(dynatrace.Setup(mgr, o, selected), "Cannot setup Dynatrace controllers")
+	if *webhookTLSCertDir != "" {
+		kingpin.FatalIfError(dynatrace.SetupWebhooks(mgr, selected), "Cannot setup Dynatrace webhooks")
+	}
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	if serr := shutdownTracing(context.Background()); serr != nil {
+		log.Info("Cannot flush traces", "error", serr)
+	}
+	kingpin.FatalIfError(err, "Cannot start controller manager")
 }
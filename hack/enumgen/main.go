@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// enumgen generates a Go string type, its constants and its kubebuilder enum
+// validation marker from an enum of a Settings 2.0 schema vendored by the
+// upstream Terraform provider, so that API types accept every value the
+// Dynatrace UI offers without hand edits.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// initialisms are the words of enum values that are not title-cased in
+// constant names.
+var initialisms = map[string]string{
+	"AWS":   "AWS",
+	"CPU":   "CPU",
+	"DCRUM": "DCRUM",
+	"EBS":  "EBS",
+	"EC2":  "EC2",
+	"ELB":  "ELB",
+	"ESXI": "ESXi",
+	"GC":   "GC",
+	"HTTP": "HTTP",
+	"NIC":  "NIC",
+	"OSI":  "OSI",
+	"PG":   "PG",
+	"PGI":  "PGI",
+	"RDS":  "RDS",
+	"VM":   "VM",
+}
+
+type schema struct {
+	SchemaID string          `json:"schemaId"`
+	Version  string          `json:"version"`
+	Enums    map[string]enum `json:"enums"`
+}
+
+type enum struct {
+	Items []struct {
+		DisplayName string `json:"displayName"`
+		Value       string `json:"value"`
+	} `json:"items"`
+}
+
+type value struct {
+	Name        string
+	Value       string
+	DisplayName string
+}
+
+var tmpl = template.Must(template.New("enum").Parse(`{{ .Header }}
+
+// Code generated by enumgen. DO NOT EDIT.
+
+package {{ .Package }}
+
+// {{ .Type }} is an enum of the {{ .SchemaID }} schema, version {{ .Version }}.
+// +kubebuilder:validation:Enum={{ .Enum }}
+type {{ .Type }} string
+
+// {{ .Type }} values.
+const (
+{{- range .Values }}
+	{{ .Name }} {{ $.Type }} = "{{ .Value }}" // {{ .DisplayName }}
+{{- end }}
+)
+
+// {{ .Type }}s are all {{ .Type }} values, in the order of the schema
+// {{- if .Extra }} followed by values it no longer offers{{ end }}.
+var {{ .Type }}s = []{{ .Type }}{
+{{- range .Values }}
+	{{ .Name }},
+{{- end }}
+}
+`))
+
+func main() {
+	var (
+		module  = flag.String("module", "github.com/dynatrace-oss/terraform-provider-dynatrace", "Go module that vendors the schema.")
+		path    = flag.String("schema", "", "Path of the schema file within the module.")
+		name    = flag.String("enum", "", "Name of the enum within the schema.")
+		typ     = flag.String("type", "", "Name of the generated Go type. Defaults to the name of the enum.")
+		pkg     = flag.String("package", "", "Package of the generated file.")
+		header  = flag.String("header", "", "File whose content is prepended to the generated file.")
+		outFile = flag.String("o", "", "Generated file.")
+		extra   = flag.String("extra", "", "Comma-separated values to keep after the schema offered by the enum, e.g. values the schema no longer offers that stored resources may still use.")
+	)
+	flag.Parse()
+
+	if *typ == "" {
+		*typ = *name
+	}
+
+	var extras []string
+	for _, v := range strings.Split(*extra, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			extras = append(extras, v)
+		}
+	}
+
+	if err := generate(*module, *path, *name, *typ, *pkg, *header, *outFile, extras); err != nil {
+		fmt.Fprintf(os.Stderr, "enumgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(module, path, name, typ, pkg, header, outFile string, extras []string) error {
+	dir, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", module).Output()
+	if err != nil {
+		return errors.Wrapf(err, "cannot find module %s", module)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(strings.TrimSpace(string(dir)), path))
+	if err != nil {
+		return errors.Wrap(err, "cannot read schema")
+	}
+	s := schema{}
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return errors.Wrap(err, "cannot parse schema")
+	}
+	e, ok := s.Enums[name]
+	if !ok {
+		return errors.Errorf("schema %s has no enum %s", s.SchemaID, name)
+	}
+
+	h, err := os.ReadFile(header) //nolint:gosec // The header is a flag of the generator.
+	if err != nil {
+		return errors.Wrap(err, "cannot read header")
+	}
+
+	values := make([]value, 0, len(e.Items)+len(extras))
+	offered := make(map[string]bool, len(e.Items))
+	for _, item := range e.Items {
+		values = append(values, value{Name: typ + constName(item.Value), Value: item.Value, DisplayName: item.DisplayName})
+		offered[item.Value] = true
+	}
+	for _, v := range extras {
+		if offered[v] {
+			continue
+		}
+		values = append(values, value{Name: typ + constName(v), Value: v, DisplayName: "No longer offered by the schema."})
+	}
+	enumValues := make([]string, len(values))
+	for i, v := range values {
+		enumValues[i] = v.Value
+	}
+
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, map[string]any{
+		"Header":   strings.TrimSpace(string(h)),
+		"Package":  pkg,
+		"Type":     typ,
+		"SchemaID": s.SchemaID,
+		"Version":  s.Version,
+		"Enum":     strings.Join(enumValues, ";"),
+		"Values":   values,
+		"Extra":    len(values) > len(e.Items),
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot render enum")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "cannot format enum")
+	}
+
+	return errors.Wrap(os.WriteFile(outFile, src, 0o644), "cannot write enum") //nolint:gosec // Generated sources are world readable.
+}
+
+// constName returns the name of the constant of the supplied enum value, e.g.
+// OSIHighCPU for OSI_HIGH_CPU.
+func constName(v string) string {
+	b := &strings.Builder{}
+	for _, w := range strings.Split(v, "_") {
+		if i, ok := initialisms[w]; ok {
+			b.WriteString(i)
+			continue
+		}
+		b.WriteString(w[:1] + strings.ToLower(w[1:]))
+	}
+	return b.String()
+}
@@ -19,10 +19,11 @@
 set -euo pipefail
 
 APIVERSION="${APIVERSION:-v1alpha1}"
-echo "Adding type ${KIND} to group ${GROUP} with version ${APIVERSION}"
+echo "Adding type ${KIND} of schema ${SCHEMA} to group ${GROUP} with version ${APIVERSION}"
 
 export GROUP
 export KIND
+export SCHEMA
 export APIVERSION
 export PROVIDER
 export PROJECT_REPO
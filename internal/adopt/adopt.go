@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adopt binds managed resources to settings objects that already
+// exist in a Dynatrace tenant.
+package adopt
+
+import (
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+)
+
+const (
+	errList         = "cannot list settings objects"
+	errFmtAmbiguous = "cannot import by name: %d settings objects are named %q"
+)
+
+// Enabled returns true if the supplied object opted into being imported by
+// name.
+func Enabled(o metav1.Object) bool {
+	v, err := strconv.ParseBool(o.GetAnnotations()[apisv1alpha1.AnnotationKeyImportByName])
+	return err == nil && v
+}
+
+// Pending returns true if the supplied object opted into being imported by
+// name but is not yet bound to a settings object. The managed reconciler
+// defaults the external name to the object's name, so that value is treated
+// the same as no external name at all.
+func Pending(o metav1.Object) bool {
+	if !Enabled(o) {
+		return false
+	}
+	en := meta.GetExternalName(o)
+	return en == "" || en == o.GetName()
+}
+
+// A Lister lists the settings objects of a single schema.
+type Lister interface {
+	List() (api.Stubs, error)
+}
+
+// ByName looks up the settings object with the supplied name and, if exactly
+// one exists, sets its ID as the external name of the supplied object. It
+// returns true if the external name was changed. Objects that are not
+// pending import are left untouched.
+func ByName(o metav1.Object, svc Lister, name string) (bool, error) {
+	if !Pending(o) {
+		return false, nil
+	}
+
+	stubs, err := svc.List()
+	if err != nil {
+		return false, errors.Wrap(err, errList)
+	}
+
+	id, matches := "", 0
+	for _, s := range stubs {
+		if s.Name == name {
+			id = s.ID
+			matches++
+		}
+	}
+
+	switch matches {
+	case 0:
+		return false, nil
+	case 1:
+		meta.SetExternalName(o, id)
+		return true, nil
+	default:
+		return false, errors.Errorf(errFmtAmbiguous, matches, name)
+	}
+}
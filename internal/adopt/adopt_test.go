@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adopt
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+)
+
+type listFn func() (api.Stubs, error)
+
+func (fn listFn) List() (api.Stubs, error) { return fn() }
+
+func object(annotations map[string]string) *metav1.ObjectMeta {
+	return &metav1.ObjectMeta{Name: "cool", Annotations: annotations}
+}
+
+func TestByName(t *testing.T) {
+	errBoom := errors.New("boom")
+	stubs := func() (api.Stubs, error) {
+		return api.Stubs{
+			{ID: "id-a", Name: "a"},
+			{ID: "id-b", Name: "b"},
+			{ID: "id-b2", Name: "b"},
+		}, nil
+	}
+
+	type args struct {
+		o    *metav1.ObjectMeta
+		svc  Lister
+		name string
+	}
+
+	type want struct {
+		adopted      bool
+		externalName string
+		err          error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotEnabled": {
+			reason: "Objects without the import annotation should not be adopted.",
+			args: args{
+				o:    object(nil),
+				svc:  listFn(func() (api.Stubs, error) { panic("should not list") }),
+				name: "a",
+			},
+			want: want{},
+		},
+		"AlreadyBound": {
+			reason: "Objects with an external name of their own should not be adopted.",
+			args: args{
+				o: object(map[string]string{
+					apisv1alpha1.AnnotationKeyImportByName: "true",
+					meta.AnnotationKeyExternalName:         "id-x",
+				}),
+				svc:  listFn(func() (api.Stubs, error) { panic("should not list") }),
+				name: "a",
+			},
+			want: want{externalName: "id-x"},
+		},
+		"Adopted": {
+			reason: "The ID of the single object with a matching name should become the external name.",
+			args: args{
+				o: object(map[string]string{
+					apisv1alpha1.AnnotationKeyImportByName: "true",
+					meta.AnnotationKeyExternalName:         "cool",
+				}),
+				svc:  listFn(stubs),
+				name: "a",
+			},
+			want: want{adopted: true, externalName: "id-a"},
+		},
+		"NoMatch": {
+			reason: "Objects should be left untouched if no settings object has a matching name.",
+			args: args{
+				o:    object(map[string]string{apisv1alpha1.AnnotationKeyImportByName: "true"}),
+				svc:  listFn(stubs),
+				name: "c",
+			},
+			want: want{},
+		},
+		"Ambiguous": {
+			reason: "An error should be returned if several settings objects have a matching name.",
+			args: args{
+				o:    object(map[string]string{apisv1alpha1.AnnotationKeyImportByName: "true"}),
+				svc:  listFn(stubs),
+				name: "b",
+			},
+			want: want{err: errors.Errorf(errFmtAmbiguous, 2, "b")},
+		},
+		"ListError": {
+			reason: "Errors listing settings objects should be returned.",
+			args: args{
+				o:    object(map[string]string{apisv1alpha1.AnnotationKeyImportByName: "true"}),
+				svc:  listFn(func() (api.Stubs, error) { return nil, errBoom }),
+				name: "a",
+			},
+			want: want{err: errors.Wrap(errBoom, errList)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			adopted, err := ByName(tc.args.o, tc.args.svc, tc.args.name)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nByName(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.adopted, adopted); diff != "" {
+				t.Errorf("\n%s\nByName(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tc.args.o)); diff != "" {
+				t.Errorf("\n%s\nByName(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
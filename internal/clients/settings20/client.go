@@ -0,0 +1,299 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package settings20 is a client for the Dynatrace Settings 2.0 API that
+// exposes the metadata of settings objects.
+package settings20
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	pathObjects = "/api/v2/settings/objects"
+
+	queryValidateOnly = "?validateOnly=true"
+
+	// listPageSize is the largest page size the API allows.
+	listPageSize = 500
+
+	errMarshalBody   = "cannot marshal request body"
+	errNewRequest    = "cannot create request"
+	errReadBody      = "cannot read response body"
+	errUnmarshalBody = "cannot unmarshal response body"
+	errNotCreated    = "the API did not return the ID of the created settings object"
+)
+
+// AttributeObjectID is the attribute of the spans of HTTP requests that
+// identifies the settings object they are about.
+const AttributeObjectID = attribute.Key("dynatrace.settings.object_id")
+
+// A Client talks to the Settings 2.0 API of a single Dynatrace environment.
+type Client struct {
+	url   string
+	token string
+	http  *http.Client
+
+	// ctx is the context requests are sent within. It is carried by the
+	// Client because the upstream settings services the Client backs do not
+	// accept one.
+	ctx context.Context
+}
+
+// An Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient configures the HTTP client used to talk to the API.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// NewClient returns a Client for the environment and API token of the
+// supplied credentials. Unless configured otherwise, the Client records a span
+// of every HTTP request it sends.
+func NewClient(creds *settings.Credentials, o ...Option) *Client {
+	c := &Client{
+		url:   strings.TrimSuffix(creds.URL, "/"),
+		token: creds.Token,
+		http:  &http.Client{Transport: otelhttp.NewTransport(objectIDTransport{http.DefaultTransport})},
+		ctx:   context.Background(),
+	}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+// WithContext returns a copy of the Client that sends its requests within
+// the supplied context, e.g. to record their spans as children of a span of
+// the context.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	cp := *c
+	cp.ctx = ctx
+	return &cp
+}
+
+// Object returns the settings object with the supplied ID.
+func (c *Client) Object(id string) (*Object, error) {
+	o := &Object{}
+	if err := c.do(id, http.MethodGet, pathObjects+"/"+url.PathEscape(id), nil, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// An objectList is a page of settings objects.
+type objectList struct {
+	Items       []Object `json:"items"`
+	NextPageKey *string  `json:"nextPageKey,omitempty"`
+}
+
+// Objects returns the ID, scope and value of every settings object of the
+// supplied schema. The objects are read a page at a time.
+func (c *Client) Objects(schemaID string) ([]Object, error) {
+	q := url.Values{"schemaIds": {schemaID}, "fields": {"objectId,scope,value"}, "pageSize": {strconv.Itoa(listPageSize)}}
+	var objects []Object
+	for {
+		page := &objectList{}
+		if err := c.do("", http.MethodGet, pathObjects+"?"+q.Encode(), nil, page); err != nil {
+			return nil, err
+		}
+		objects = append(objects, page.Items...)
+		if page.NextPageKey == nil {
+			return objects, nil
+		}
+		q = url.Values{"nextPageKey": {*page.NextPageKey}}
+	}
+}
+
+// An objectUpdate is the body of a request updating a settings object.
+type objectUpdate struct {
+	UpdateToken string `json:"updateToken,omitempty"`
+	Value       any    `json:"value"`
+}
+
+// UpdateObject replaces the value of the settings object with the supplied
+// ID. If updateToken is not empty the update is rejected with a conflict
+// unless the object is still at the version the token was observed at.
+func (c *Client) UpdateObject(id string, value any, updateToken string) error {
+	return c.do(id, http.MethodPut, pathObjects+"/"+url.PathEscape(id), &objectUpdate{UpdateToken: updateToken, Value: value}, nil)
+}
+
+// An objectCreate is an element of the body of a request creating settings
+// objects.
+type objectCreate struct {
+	SchemaID string `json:"schemaId"`
+	Scope    string `json:"scope"`
+	Value    any    `json:"value"`
+}
+
+// An objectCreated is an element of the response to a request creating
+// settings objects.
+type objectCreated struct {
+	ObjectID string `json:"objectId"`
+}
+
+// CreateObject creates a settings object of the supplied schema and scope and
+// returns its ID.
+func (c *Client) CreateObject(schemaID, scope string, value any) (string, error) {
+	created := []objectCreated{}
+	if err := c.do("", http.MethodPost, pathObjects, []objectCreate{{SchemaID: schemaID, Scope: scope, Value: value}}, &created); err != nil {
+		return "", err
+	}
+	if len(created) == 0 || created[0].ObjectID == "" {
+		return "", errors.New(errNotCreated)
+	}
+	return created[0].ObjectID, nil
+}
+
+// DeleteObject deletes the settings object with the supplied ID.
+func (c *Client) DeleteObject(id string) error {
+	return c.do(id, http.MethodDelete, pathObjects+"/"+url.PathEscape(id), nil, nil)
+}
+
+// ValidateCreate validates a new settings object of the supplied schema and
+// scope without creating it.
+func (c *Client) ValidateCreate(schemaID, scope string, value any) error {
+	return c.do("", http.MethodPost, pathObjects+queryValidateOnly, []objectCreate{{SchemaID: schemaID, Scope: scope, Value: value}}, nil)
+}
+
+// ValidateUpdate validates replacing the value of the settings object with
+// the supplied ID without updating it.
+func (c *Client) ValidateUpdate(id string, value any) error {
+	return c.do(id, http.MethodPut, pathObjects+"/"+url.PathEscape(id)+queryValidateOnly, &objectUpdate{Value: value}, nil)
+}
+
+// IsNotFound returns true if the supplied error reports that a settings
+// object does not exist.
+func IsNotFound(err error) bool {
+	return hasCode(err, http.StatusNotFound)
+}
+
+// IsConflict returns true if the supplied error reports that a settings
+// object was modified since its update token was observed.
+func IsConflict(err error) bool {
+	return hasCode(err, http.StatusConflict)
+}
+
+func hasCode(err error, code int) bool {
+	var re rest.Error
+	return errors.As(err, &re) && re.Code == code
+}
+
+// do sends a request about the settings object with the supplied ID, if any,
+// with the JSON encoding of in as its body and decodes the response into out.
+// Responses with a status code other than 2xx are returned as a rest.Error,
+// just like the upstream Dynatrace client does.
+func (c *Client) do(id, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return errors.Wrap(err, errMarshalBody)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	ctx := c.ctx
+	if id != "" {
+		ctx = context.WithValue(ctx, objectIDKey{}, id)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, body)
+	if err != nil {
+		return errors.Wrap(err, errNewRequest)
+	}
+	req.Header.Set("Authorization", "Api-Token "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck // Nothing to do about it.
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, errReadBody)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newError(method, req.URL.String(), resp.StatusCode, b)
+	}
+
+	if out == nil || len(b) == 0 {
+		return nil
+	}
+	return errors.Wrap(json.Unmarshal(b, out), errUnmarshalBody)
+}
+
+type objectIDKey struct{}
+
+// An objectIDTransport records the ID of the settings object a request is
+// about on the span of the request. It must be wrapped by the transport that
+// starts the span.
+type objectIDTransport struct {
+	http.RoundTripper
+}
+
+func (t objectIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if id, ok := req.Context().Value(objectIDKey{}).(string); ok {
+		trace.SpanFromContext(req.Context()).SetAttributes(AttributeObjectID.String(id))
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
+
+// An errorEnvelope wraps the error of a failed request. Requests creating
+// settings objects return one envelope per object.
+type errorEnvelope struct {
+	Error *rest.Error `json:"error"`
+}
+
+func newError(method, u string, code int, body []byte) error {
+	env := errorEnvelope{}
+	if err := json.Unmarshal(body, &env); err != nil {
+		envs := []errorEnvelope{}
+		if err := json.Unmarshal(body, &envs); err == nil && len(envs) > 0 {
+			env = envs[0]
+		}
+	}
+	if env.Error == nil {
+		env.Error = &rest.Error{Message: fmt.Sprintf("%s %s: %s", method, u, http.StatusText(code))}
+	}
+	env.Error.Code = code
+	env.Error.Method = method
+	env.Error.URL = u
+	return *env.Error
+}
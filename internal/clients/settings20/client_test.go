@@ -0,0 +1,408 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package settings20
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestObject(t *testing.T) {
+	type want struct {
+		o   *Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		handler http.HandlerFunc
+		want    want
+	}{
+		"Success": {
+			reason: "The object and its metadata should be returned.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v2/settings/objects/some-id" {
+					t.Errorf("unexpected path %q", r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Api-Token secret" {
+					t.Errorf("unexpected Authorization header %q", got)
+				}
+				_, _ = w.Write([]byte(`{"objectId":"some-id","schemaId":"builtin:alerting.profile","schemaVersion":"8.3","scope":"environment","created":1690000000000,"createdBy":"alice","modified":1690000060000,"modifiedBy":"bob","updateToken":"token","value":{"name":"cool"}}`))
+			},
+			want: want{
+				o: &Object{
+					ObjectID:      "some-id",
+					SchemaID:      "builtin:alerting.profile",
+					SchemaVersion: "8.3",
+					Scope:         "environment",
+					Created:       1690000000000,
+					CreatedBy:     "alice",
+					Modified:      1690000060000,
+					ModifiedBy:    "bob",
+					UpdateToken:   "token",
+					Value:         []byte(`{"name":"cool"}`),
+				},
+			},
+		},
+		"NotFound": {
+			reason: "API errors should be returned as a rest.Error.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Settings not found"}}`))
+			},
+			want: want{
+				err: rest.Error{Code: http.StatusNotFound, Message: "Settings not found", Method: http.MethodGet},
+			},
+		},
+		"UnparseableError": {
+			reason: "API errors without a JSON body should still be returned as a rest.Error.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			want: want{
+				err: rest.Error{Code: http.StatusBadGateway, Method: http.MethodGet, Message: "GET /api/v2/settings/objects/some-id: Bad Gateway"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			defer srv.Close()
+
+			c := NewClient(&settings.Credentials{URL: srv.URL + "/", Token: "secret"}, WithHTTPClient(srv.Client()))
+			got, err := c.Object("some-id")
+			if re, ok := err.(rest.Error); ok {
+				re.Message = strings.ReplaceAll(re.Message, srv.URL, "")
+				err = re
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Object(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\nc.Object(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdateObject(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		updateToken string
+		status      int
+		wantBody    string
+		wantErr     error
+	}{
+		"WithToken": {
+			reason:      "The update token should be sent along with the value.",
+			updateToken: "token",
+			status:      http.StatusOK,
+			wantBody:    `{"updateToken":"token","value":{"name":"cool"}}`,
+		},
+		"WithoutToken": {
+			reason:   "No update token should be sent if none was observed.",
+			status:   http.StatusOK,
+			wantBody: `{"value":{"name":"cool"}}`,
+		},
+		"Conflict": {
+			reason:      "A conflict should be reported as such.",
+			updateToken: "stale",
+			status:      http.StatusConflict,
+			wantBody:    `{"updateToken":"stale","value":{"name":"cool"}}`,
+			wantErr:     rest.Error{Code: http.StatusConflict, Message: "modified concurrently"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut {
+					t.Errorf("unexpected method %q", r.Method)
+				}
+				b, _ := io.ReadAll(r.Body)
+				if diff := cmp.Diff(tc.wantBody, string(b)); diff != "" {
+					t.Errorf("\n%s\nrequest body: -want, +got:\n%s\n", tc.reason, diff)
+				}
+				w.WriteHeader(tc.status)
+				if tc.status == http.StatusConflict {
+					_, _ = w.Write([]byte(`{"error":{"code":409,"message":"modified concurrently"}}`))
+				}
+			}))
+			defer srv.Close()
+
+			c := NewClient(&settings.Credentials{URL: srv.URL, Token: "secret"}, WithHTTPClient(srv.Client()))
+			err := c.UpdateObject("some-id", map[string]string{"name": "cool"}, tc.updateToken)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.UpdateObject(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantErr != nil, IsConflict(err)); diff != "" {
+				t.Errorf("\n%s\nIsConflict(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := `[{"code":400,"error":{"code":400,"message":"Constraints violated.","constraintViolations":[{"path":"rules/0/value","message":"must not be empty"}]}}]`
+
+	cases := map[string]struct {
+		reason     string
+		validate   func(c *Client) error
+		status     int
+		response   string
+		wantMethod string
+		wantURI    string
+		wantBody   string
+		wantErr    string
+	}{
+		"Create": {
+			reason: "A new settings object should be validated without creating it.",
+			validate: func(c *Client) error {
+				return c.ValidateCreate("builtin:tags.auto-tagging", "environment", map[string]string{"name": "cool"})
+			},
+			status:     http.StatusOK,
+			wantMethod: http.MethodPost,
+			wantURI:    "/api/v2/settings/objects?validateOnly=true",
+			wantBody:   `[{"schemaId":"builtin:tags.auto-tagging","scope":"environment","value":{"name":"cool"}}]`,
+		},
+		"CreateInvalid": {
+			reason: "The constraint violations of the first invalid object should be returned.",
+			validate: func(c *Client) error {
+				return c.ValidateCreate("builtin:tags.auto-tagging", "environment", map[string]string{"name": "cool"})
+			},
+			status:     http.StatusBadRequest,
+			response:   invalid,
+			wantMethod: http.MethodPost,
+			wantURI:    "/api/v2/settings/objects?validateOnly=true",
+			wantBody:   `[{"schemaId":"builtin:tags.auto-tagging","scope":"environment","value":{"name":"cool"}}]`,
+			wantErr:    "Constraints violated.\nrules/0/value: must not be empty",
+		},
+		"Update": {
+			reason:     "A new value of a settings object should be validated without updating it.",
+			validate:   func(c *Client) error { return c.ValidateUpdate("some-id", map[string]string{"name": "cool"}) },
+			status:     http.StatusOK,
+			wantMethod: http.MethodPut,
+			wantURI:    "/api/v2/settings/objects/some-id?validateOnly=true",
+			wantBody:   `{"value":{"name":"cool"}}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tc.wantMethod {
+					t.Errorf("unexpected method %q", r.Method)
+				}
+				if diff := cmp.Diff(tc.wantURI, r.URL.RequestURI()); diff != "" {
+					t.Errorf("\n%s\nrequest URI: -want, +got:\n%s\n", tc.reason, diff)
+				}
+				b, _ := io.ReadAll(r.Body)
+				if diff := cmp.Diff(tc.wantBody, string(b)); diff != "" {
+					t.Errorf("\n%s\nrequest body: -want, +got:\n%s\n", tc.reason, diff)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer srv.Close()
+
+			err := tc.validate(NewClient(&settings.Credentials{URL: srv.URL, Token: "secret"}, WithHTTPClient(srv.Client())))
+			if diff := cmp.Diff(tc.wantErr != "", IsInvalid(err)); diff != "" {
+				t.Errorf("\n%s\nIsInvalid(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				if diff := cmp.Diff(tc.wantErr, Message(err)); diff != "" {
+					t.Errorf("\n%s\nMessage(...): -want, +got:\n%s\n", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestObjects(t *testing.T) {
+	type want struct {
+		objects []Object
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		handler http.HandlerFunc
+		want    want
+	}{
+		"Pages": {
+			reason: "The objects of every page should be returned.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.RawQuery {
+				case "fields=objectId%2Cscope%2Cvalue&pageSize=500&schemaIds=builtin%3Atags.auto-tagging":
+					_, _ = w.Write([]byte(`{"items":[{"objectId":"a","scope":"environment","value":{"name":"a"}}],"nextPageKey":"next"}`))
+				case "nextPageKey=next":
+					_, _ = w.Write([]byte(`{"items":[{"objectId":"b","scope":"environment","value":{"name":"b"}}]}`))
+				default:
+					t.Errorf("unexpected query %q", r.URL.RawQuery)
+				}
+			},
+			want: want{
+				objects: []Object{
+					{ObjectID: "a", Scope: "environment", Value: []byte(`{"name":"a"}`)},
+					{ObjectID: "b", Scope: "environment", Value: []byte(`{"name":"b"}`)},
+				},
+			},
+		},
+		"Throttled": {
+			reason: "API errors should be returned as a rest.Error.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"error":{"code":429,"message":"Too many requests"}}`))
+			},
+			want: want{
+				err: rest.Error{Code: http.StatusTooManyRequests, Message: "Too many requests", Method: http.MethodGet},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			defer srv.Close()
+
+			c := NewClient(&settings.Credentials{URL: srv.URL, Token: "secret"}, WithHTTPClient(srv.Client()))
+			got, err := c.Objects("builtin:tags.auto-tagging")
+			if re, ok := err.(rest.Error); ok {
+				re.URL = ""
+				err = re
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Objects(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objects, got); diff != "" {
+				t.Errorf("\n%s\nc.Objects(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateObject(t *testing.T) {
+	type want struct {
+		id  string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		status   int
+		response string
+		want     want
+	}{
+		"Created": {
+			reason:   "The ID of the created object should be returned.",
+			status:   http.StatusOK,
+			response: `[{"code":200,"objectId":"some-id"}]`,
+			want:     want{id: "some-id"},
+		},
+		"NoID": {
+			reason:   "A response without the ID of the created object should be an error.",
+			status:   http.StatusOK,
+			response: `[]`,
+			want:     want{err: errors.New(errNotCreated)},
+		},
+		"Invalid": {
+			reason:   "The error of the rejected object should be returned.",
+			status:   http.StatusBadRequest,
+			response: `[{"code":400,"error":{"code":400,"message":"Constraints violated."}}]`,
+			want:     want{err: rest.Error{Code: http.StatusBadRequest, Message: "Constraints violated.", Method: http.MethodPost}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.RequestURI() != "/api/v2/settings/objects" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.RequestURI())
+				}
+				b, _ := io.ReadAll(r.Body)
+				if diff := cmp.Diff(`[{"schemaId":"builtin:tags.auto-tagging","scope":"environment","value":{"name":"cool"}}]`, string(b)); diff != "" {
+					t.Errorf("\n%s\nrequest body: -want, +got:\n%s\n", tc.reason, diff)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer srv.Close()
+
+			c := NewClient(&settings.Credentials{URL: srv.URL, Token: "secret"}, WithHTTPClient(srv.Client()))
+			id, err := c.CreateObject("builtin:tags.auto-tagging", "environment", map[string]string{"name": "cool"})
+			if re, ok := err.(rest.Error); ok {
+				re.URL = ""
+				err = re
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.CreateObject(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.id, id); diff != "" {
+				t.Errorf("\n%s\nc.CreateObject(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDeleteObject(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		status int
+		want   bool
+	}{
+		"Deleted": {
+			reason: "Deleting an existing object should succeed.",
+			status: http.StatusNoContent,
+		},
+		"NotFound": {
+			reason: "Deleting an object that does not exist should be reported as not found.",
+			status: http.StatusNotFound,
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete || r.URL.Path != "/api/v2/settings/objects/some-id" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			c := NewClient(&settings.Credentials{URL: srv.URL, Token: "secret"}, WithHTTPClient(srv.Client()))
+			err := c.DeleteObject("some-id")
+			if diff := cmp.Diff(tc.want, IsNotFound(err)); diff != "" {
+				t.Errorf("\n%s\nIsNotFound(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if !tc.want && err != nil {
+				t.Errorf("\n%s\nc.DeleteObject(...): %v", tc.reason, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package settings20
+
+import (
+	"net/http"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+)
+
+// Reason classifies the supplied error returned by the API. It returns an
+// empty reason if the error is not an API error or its status code has no
+// distinct reason.
+func Reason(err error) xpv1.ConditionReason {
+	var re rest.Error
+	if !errors.As(err, &re) {
+		return ""
+	}
+
+	switch {
+	case re.Code == http.StatusBadRequest, re.Code == http.StatusUnprocessableEntity:
+		return v1alpha1.ReasonInvalidSpec
+	case re.Code == http.StatusUnauthorized:
+		return v1alpha1.ReasonUnauthorized
+	case re.Code == http.StatusForbidden:
+		return v1alpha1.ReasonForbidden
+	case re.Code == http.StatusTooManyRequests:
+		return v1alpha1.ReasonThrottled
+	case re.Code >= http.StatusInternalServerError:
+		return v1alpha1.ReasonServerError
+	}
+
+	return ""
+}
+
+// IsInvalid returns true if the supplied error reports that the API rejected
+// a settings object as invalid. Sending the same object again cannot succeed.
+func IsInvalid(err error) bool {
+	return Reason(err) == v1alpha1.ReasonInvalidSpec
+}
+
+// Message returns the message of the supplied API error followed by one
+// "path: message" line per constraint violation it reports.
+func Message(err error) string {
+	var re rest.Error
+	if !errors.As(err, &re) {
+		return err.Error()
+	}
+
+	b := &strings.Builder{}
+	b.WriteString(re.Message)
+	for _, v := range re.ConstraintViolations {
+		b.WriteString("\n")
+		if v.Path != "" {
+			b.WriteString(v.Path + ": ")
+		}
+		b.WriteString(v.Message)
+	}
+	return b.String()
+}
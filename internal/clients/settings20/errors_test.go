@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package settings20
+
+import (
+	"net/http"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+)
+
+func TestReason(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   xpv1.ConditionReason
+	}{
+		"NotAPIError": {
+			reason: "Errors that are not API errors should not be classified.",
+			err:    errors.New("boom"),
+		},
+		"BadRequest": {
+			reason: "A 400 should be classified as an invalid spec, even if wrapped.",
+			err:    errors.Wrap(rest.Error{Code: http.StatusBadRequest}, "cannot create"),
+			want:   v1alpha1.ReasonInvalidSpec,
+		},
+		"UnprocessableEntity": {
+			reason: "A 422 should be classified as an invalid spec.",
+			err:    rest.Error{Code: http.StatusUnprocessableEntity},
+			want:   v1alpha1.ReasonInvalidSpec,
+		},
+		"Unauthorized": {
+			reason: "A 401 should be classified as unauthorized.",
+			err:    rest.Error{Code: http.StatusUnauthorized},
+			want:   v1alpha1.ReasonUnauthorized,
+		},
+		"Forbidden": {
+			reason: "A 403 should be classified as forbidden.",
+			err:    rest.Error{Code: http.StatusForbidden},
+			want:   v1alpha1.ReasonForbidden,
+		},
+		"Throttled": {
+			reason: "A 429 should be classified as throttled.",
+			err:    rest.Error{Code: http.StatusTooManyRequests},
+			want:   v1alpha1.ReasonThrottled,
+		},
+		"ServerError": {
+			reason: "A 5xx should be classified as a server error.",
+			err:    rest.Error{Code: http.StatusBadGateway},
+			want:   v1alpha1.ReasonServerError,
+		},
+		"Conflict": {
+			reason: "A 409 has no distinct reason.",
+			err:    rest.Error{Code: http.StatusConflict},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Reason(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nReason(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   string
+	}{
+		"NotAPIError": {
+			reason: "The message of errors that are not API errors should be returned as is.",
+			err:    errors.New("boom"),
+			want:   "boom",
+		},
+		"ConstraintViolations": {
+			reason: "Each constraint violation should be listed on its own line.",
+			err: errors.Wrap(rest.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Constraints violated.",
+				ConstraintViolations: []rest.ConstraintViolation{
+					{Path: "rules/0/value", Message: "must not be empty"},
+					{Message: "name must be unique"},
+				},
+			}, "cannot create"),
+			want: "Constraints violated.\nrules/0/value: must not be empty\nname must be unique",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Message(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nMessage(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,513 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake implements an in-memory Dynatrace Settings 2.0 API for tests.
+// It serves the objects endpoints the provider and the upstream Terraform
+// provider use, versions every object so that stale update tokens conflict,
+// and returns injected errors on demand.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+)
+
+const (
+	// Token is the API token the Server accepts.
+	Token = "fake-token"
+
+	// Author is the user the Server records as the author of objects
+	// written through the API.
+	Author = "crossplane"
+
+	// ScopeEnvironment is the scope of objects created without one.
+	ScopeEnvironment = "environment"
+
+	pathObjects     = "/api/v2/settings/objects"
+	defaultPageSize = 100
+)
+
+// A Fault is an error the Server returns instead of handling a request.
+type Fault struct {
+	// Method of the requests that fail, e.g. GET. Any method matches if it
+	// is empty.
+	Method string
+
+	// Path of the requests that fail, e.g. /api/v2/settings/objects/some-id.
+	// Any path matches if it is empty.
+	Path string
+
+	// ValidateOnly fails validation requests, which only validate objects,
+	// instead of requests that read or write objects.
+	ValidateOnly bool
+
+	// Code is the HTTP status code of the error, e.g. 429.
+	Code int
+
+	// Message of the error. Defaults to the status text of the code.
+	Message string
+
+	// ConstraintViolations reported by the error.
+	ConstraintViolations []rest.ConstraintViolation
+}
+
+func (f Fault) matches(r *http.Request) bool {
+	return (f.Method == "" || f.Method == r.Method) &&
+		(f.Path == "" || f.Path == r.URL.Path) &&
+		f.ValidateOnly == validateOnly(r)
+}
+
+// An Object is a settings object stored by the Server.
+type Object struct {
+	ObjectID      string          `json:"objectId"`
+	SchemaID      string          `json:"schemaId"`
+	SchemaVersion string          `json:"schemaVersion"`
+	Scope         string          `json:"scope"`
+	Author        string          `json:"author,omitempty"`
+	Created       int64           `json:"created,omitempty"`
+	CreatedBy     string          `json:"createdBy,omitempty"`
+	Modified      int64           `json:"modified,omitempty"`
+	ModifiedBy    string          `json:"modifiedBy,omitempty"`
+	UpdateToken   string          `json:"updateToken,omitempty"`
+	Value         json.RawMessage `json:"value"`
+
+	version int
+}
+
+// A Server is an in-memory Settings 2.0 API served over HTTP. Its zero value
+// is not usable; use NewServer.
+//
+// Note that the settings services of the upstream Terraform provider wait at
+// least five seconds before retrying requests that return 429 Too Many
+// Requests, so tests should only throttle the requests the provider makes
+// itself: reading, updating and validating objects.
+type Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	objects map[string]*Object
+	order   []string
+	faults  []Fault
+	next    int
+	now     func() time.Time
+}
+
+// NewServer starts a Server. Callers should Close it when finished.
+func NewServer() *Server {
+	s := &Server{objects: map[string]*Object{}, now: time.Now}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Credentials returns credentials of a ProviderConfig that connect to the
+// Server.
+func (s *Server) Credentials() []byte {
+	b, _ := json.Marshal(map[string]string{"url": s.URL, "token": Token})
+	return b
+}
+
+// Inject makes the next request that matches the supplied fault fail with
+// its error. Faults are matched in the order they were injected.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, f)
+}
+
+// Create stores a new object of the supplied schema and returns its ID, as if
+// it was created by someone else.
+func (s *Server) Create(schemaID, scope string, value any) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, err := s.create(schemaID, "", scope, mustMarshal(value), "someone-else")
+	if err != nil {
+		panic(err)
+	}
+	return o.ObjectID
+}
+
+// Update replaces the value of the object with the supplied ID, as if it was
+// modified by someone else. It returns false if there is no such object.
+func (s *Server) Update(id string, value any) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.objects[id]
+	if !ok {
+		return false
+	}
+	s.update(o, mustMarshal(value), "someone-else")
+	return true
+}
+
+// Delete deletes the object with the supplied ID, as if it was deleted by
+// someone else. It returns false if there is no such object.
+func (s *Server) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.delete(id)
+}
+
+// Object returns a copy of the object with the supplied ID, if it exists.
+func (s *Server) Object(id string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.objects[id]
+	if !ok {
+		return Object{}, false
+	}
+	return *o, true
+}
+
+// Objects returns copies of the objects of the supplied schema, in the order
+// they were created.
+func (s *Server) Objects(schemaID string) []Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Object
+	for _, o := range s.list(schemaID) {
+		out = append(out, *o)
+	}
+	return out
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Api-Token "+Token {
+		writeError(w, rest.Error{Code: http.StatusUnauthorized, Message: "Missing authorization parameter."})
+		return
+	}
+
+	for i, f := range s.faults {
+		if !f.matches(r) {
+			continue
+		}
+		s.faults = append(s.faults[:i], s.faults[i+1:]...)
+		if f.Message == "" {
+			f.Message = http.StatusText(f.Code)
+		}
+		writeError(w, rest.Error{Code: f.Code, Message: f.Message, ConstraintViolations: f.ConstraintViolations})
+		return
+	}
+
+	id, hasID := strings.CutPrefix(r.URL.Path, pathObjects+"/")
+	switch {
+	case r.URL.Path == pathObjects && r.Method == http.MethodGet:
+		s.serveList(w, r)
+	case r.URL.Path == pathObjects && r.Method == http.MethodPost:
+		s.serveCreate(w, r)
+	case hasID && r.Method == http.MethodGet:
+		s.serveGet(w, id)
+	case hasID && r.Method == http.MethodPut:
+		s.serveUpdate(w, r, id)
+	case hasID && r.Method == http.MethodDelete:
+		s.serveDelete(w, id)
+	default:
+		writeError(w, rest.Error{Code: http.StatusNotFound, Message: fmt.Sprintf("%s %s is not supported", r.Method, r.URL.Path)})
+	}
+}
+
+type listResponse struct {
+	Items       []*Object `json:"items"`
+	TotalCount  int       `json:"totalCount"`
+	PageSize    int       `json:"pageSize"`
+	NextPageKey *string   `json:"nextPageKey,omitempty"`
+}
+
+// serveList lists objects a page at a time. The next page key encodes the
+// query of the first page and the offset of the next one.
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if key := q.Get("nextPageKey"); key != "" {
+		parsed, err := url.ParseQuery(key)
+		if err != nil {
+			writeError(w, rest.Error{Code: http.StatusBadRequest, Message: "Invalid nextPageKey."})
+			return
+		}
+		q = parsed
+	}
+
+	schemaIDs := q.Get("schemaIds")
+	if schemaIDs == "" {
+		writeError(w, rest.Error{Code: http.StatusBadRequest, Message: "Either schemaIds or nextPageKey is required."})
+		return
+	}
+	var all []*Object
+	for _, id := range strings.Split(schemaIDs, ",") {
+		all = append(all, s.list(id)...)
+	}
+
+	size, offset := defaultPageSize, 0
+	if v, err := strconv.Atoi(q.Get("pageSize")); err == nil && v > 0 {
+		size = v
+	}
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil && v > 0 && v < len(all) {
+		offset = v
+	}
+
+	resp := listResponse{TotalCount: len(all), PageSize: size}
+	end := offset + size
+	if end < len(all) {
+		next := url.Values{"schemaIds": {schemaIDs}, "pageSize": {strconv.Itoa(size)}, "offset": {strconv.Itoa(end)}}.Encode()
+		resp.NextPageKey = &next
+	} else {
+		end = len(all)
+	}
+	resp.Items = all[offset:end]
+	if resp.Items == nil {
+		resp.Items = []*Object{}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type createRequest struct {
+	SchemaID      string          `json:"schemaId"`
+	SchemaVersion string          `json:"schemaVersion"`
+	Scope         string          `json:"scope"`
+	Value         json.RawMessage `json:"value"`
+}
+
+type createResponse struct {
+	Code     int         `json:"code"`
+	ObjectID string      `json:"objectId,omitempty"`
+	Error    *rest.Error `json:"error,omitempty"`
+}
+
+// serveCreate creates or, if the request is validate-only, validates the
+// objects in the request. Like the Settings API, it creates none of them if
+// any is invalid.
+func (s *Server) serveCreate(w http.ResponseWriter, r *http.Request) {
+	var reqs []createRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		writeError(w, rest.Error{Code: http.StatusBadRequest, Message: "Could not map JSON: " + err.Error()})
+		return
+	}
+
+	for _, c := range reqs {
+		if err := validate(c.SchemaID, c.Value); err != nil {
+			writeJSON(w, err.Code, []createResponse{{Code: err.Code, Error: err}})
+			return
+		}
+	}
+
+	resps := make([]createResponse, len(reqs))
+	for i, c := range reqs {
+		resps[i] = createResponse{Code: http.StatusOK}
+		if validateOnly(r) {
+			continue
+		}
+		o, err := s.create(c.SchemaID, c.SchemaVersion, c.Scope, c.Value, Author)
+		if err != nil {
+			writeJSON(w, err.Code, []createResponse{{Code: err.Code, Error: err}})
+			return
+		}
+		resps[i].ObjectID = o.ObjectID
+	}
+	writeJSON(w, http.StatusOK, resps)
+}
+
+func (s *Server) serveGet(w http.ResponseWriter, id string) {
+	o, ok := s.objects[id]
+	if !ok {
+		writeError(w, notFound(id))
+		return
+	}
+	writeJSON(w, http.StatusOK, o)
+}
+
+type updateRequest struct {
+	UpdateToken string          `json:"updateToken"`
+	Value       json.RawMessage `json:"value"`
+}
+
+// serveUpdate updates or, if the request is validate-only, validates the
+// object with the supplied ID. Updates that carry a token of an earlier
+// version of the object conflict.
+func (s *Server) serveUpdate(w http.ResponseWriter, r *http.Request, id string) {
+	u := updateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		writeError(w, rest.Error{Code: http.StatusBadRequest, Message: "Could not map JSON: " + err.Error()})
+		return
+	}
+
+	o, ok := s.objects[id]
+	if !ok {
+		writeError(w, notFound(id))
+		return
+	}
+	if err := validate(o.SchemaID, u.Value); err != nil {
+		writeError(w, *err)
+		return
+	}
+	if validateOnly(r) {
+		writeJSON(w, http.StatusOK, struct{}{})
+		return
+	}
+	if u.UpdateToken != "" && u.UpdateToken != o.UpdateToken {
+		writeError(w, rest.Error{Code: http.StatusConflict, Message: "Conflicting modification: the object was modified since the update token was issued."})
+		return
+	}
+
+	s.update(o, u.Value, Author)
+	writeJSON(w, http.StatusOK, map[string]string{"code": "200", "objectId": id})
+}
+
+func (s *Server) serveDelete(w http.ResponseWriter, id string) {
+	if !s.delete(id) {
+		writeError(w, notFound(id))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) create(schemaID, schemaVersion, scope string, value json.RawMessage, author string) (*Object, *rest.Error) {
+	if err := validate(schemaID, value); err != nil {
+		return nil, err
+	}
+	if scope == "" {
+		scope = ScopeEnvironment
+	}
+	if schemaVersion == "" {
+		schemaVersion = "1.0.0"
+	}
+
+	s.next++
+	now := s.now().UnixMilli()
+	o := &Object{
+		ObjectID:      fmt.Sprintf("object-%d", s.next),
+		SchemaID:      schemaID,
+		SchemaVersion: schemaVersion,
+		Scope:         scope,
+		Author:        author,
+		Created:       now,
+		CreatedBy:     author,
+		Modified:      now,
+		ModifiedBy:    author,
+		Value:         value,
+		version:       1,
+	}
+	o.UpdateToken = token(o)
+	s.objects[o.ObjectID] = o
+	s.order = append(s.order, o.ObjectID)
+	return o, nil
+}
+
+func (s *Server) update(o *Object, value json.RawMessage, author string) {
+	o.Value = value
+	o.Modified = s.now().UnixMilli()
+	o.ModifiedBy = author
+	o.version++
+	o.UpdateToken = token(o)
+}
+
+func (s *Server) delete(id string) bool {
+	if _, ok := s.objects[id]; !ok {
+		return false
+	}
+	delete(s.objects, id)
+	for i, oid := range s.order {
+		if oid == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (s *Server) list(schemaID string) []*Object {
+	var out []*Object
+	for _, id := range s.order {
+		if o := s.objects[id]; o.SchemaID == schemaID {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// validate returns an error if the supplied value cannot be an object of the
+// supplied schema. The Server does not know the schemas, so it only checks
+// that the value is a JSON object.
+func validate(schemaID string, value json.RawMessage) *rest.Error {
+	if schemaID == "" {
+		return &rest.Error{Code: http.StatusBadRequest, Message: "Validation failed", ConstraintViolations: []rest.ConstraintViolation{
+			{Path: "schemaId", Message: "must not be empty"},
+		}}
+	}
+	v := map[string]json.RawMessage{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return &rest.Error{Code: http.StatusBadRequest, Message: "Validation failed", ConstraintViolations: []rest.ConstraintViolation{
+			{Path: "value", Message: "must be an object"},
+		}}
+	}
+	return nil
+}
+
+// validateOnly returns true if the supplied request only validates objects.
+func validateOnly(r *http.Request) bool {
+	return r.URL.Query().Get("validateOnly") == "true"
+}
+
+// token returns the update token of the current version of the supplied
+// object.
+func token(o *Object) string {
+	return fmt.Sprintf("%s.v%d", o.ObjectID, o.version)
+}
+
+func notFound(id string) rest.Error {
+	return rest.Error{Code: http.StatusNotFound, Message: fmt.Sprintf("Settings not found: %s", id)}
+}
+
+type errorEnvelope struct {
+	Error rest.Error `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, err rest.Error) {
+	writeJSON(w, err.Code, errorEnvelope{Error: err})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// mustMarshal marshals the supplied value, which is either a settings object
+// value or its JSON encoding.
+func mustMarshal(v any) json.RawMessage {
+	switch v := v.(type) {
+	case json.RawMessage:
+		return v
+	case []byte:
+		return v
+	case string:
+		return json.RawMessage(v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// do sends a request to the supplied server and returns the status code and
+// body of its response.
+func do(t *testing.T, s *Server, method, path, token, body string) (int, string) {
+	t.Helper()
+
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, s.URL+path, r)
+	if err != nil {
+		t.Fatalf("http.NewRequest(...): %v", err)
+	}
+	req.Header.Set("Authorization", "Api-Token "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // Nothing to do about it.
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("io.ReadAll(...): %v", err)
+	}
+	return resp.StatusCode, strings.TrimSpace(string(b))
+}
+
+type step struct {
+	method string
+	path   string
+	token  string
+	body   string
+	code   int
+}
+
+func TestServer(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		faults []Fault
+		steps  func(id string) []step
+	}{
+		"Unauthorized": {
+			reason: "Requests with another token should be rejected.",
+			steps: func(id string) []step {
+				return []step{{method: http.MethodGet, path: pathObjects + "/" + id, token: "other", code: http.StatusUnauthorized}}
+			},
+		},
+		"CreateAndDelete": {
+			reason: "Created objects should be readable until they are deleted.",
+			steps: func(_ string) []step {
+				return []step{
+					{method: http.MethodPost, path: pathObjects, body: `[{"schemaId":"builtin:cool","value":{"name":"new"}}]`, code: http.StatusOK},
+					{method: http.MethodGet, path: pathObjects + "/object-2", code: http.StatusOK},
+					{method: http.MethodDelete, path: pathObjects + "/object-2", code: http.StatusNoContent},
+					{method: http.MethodGet, path: pathObjects + "/object-2", code: http.StatusNotFound},
+					{method: http.MethodDelete, path: pathObjects + "/object-2", code: http.StatusNotFound},
+				}
+			},
+		},
+		"ValidateOnly": {
+			reason: "Validation requests should not write objects, and invalid values should be rejected.",
+			steps: func(id string) []step {
+				return []step{
+					{method: http.MethodPost, path: pathObjects + "?validateOnly=true", body: `[{"schemaId":"builtin:cool","value":{"name":"new"}}]`, code: http.StatusOK},
+					{method: http.MethodGet, path: pathObjects + "/object-2", code: http.StatusNotFound},
+					{method: http.MethodPut, path: pathObjects + "/" + id + "?validateOnly=true", body: `{"value":"not an object"}`, code: http.StatusBadRequest},
+				}
+			},
+		},
+		"StaleUpdateToken": {
+			reason: "Updates with the token of an earlier version should conflict.",
+			steps: func(id string) []step {
+				return []step{
+					{method: http.MethodPut, path: pathObjects + "/" + id, body: `{"updateToken":"` + id + `.v1","value":{"name":"updated"}}`, code: http.StatusOK},
+					{method: http.MethodPut, path: pathObjects + "/" + id, body: `{"updateToken":"` + id + `.v1","value":{"name":"again"}}`, code: http.StatusConflict},
+					{method: http.MethodPut, path: pathObjects + "/" + id, body: `{"updateToken":"` + id + `.v2","value":{"name":"again"}}`, code: http.StatusOK},
+				}
+			},
+		},
+		"Faults": {
+			reason: "Each fault should fail the next matching request only, and only validation requests if it is validate-only.",
+			faults: []Fault{
+				{Method: http.MethodPut, ValidateOnly: true, Code: http.StatusBadRequest},
+				{Method: http.MethodGet, Code: http.StatusTooManyRequests},
+			},
+			steps: func(id string) []step {
+				return []step{
+					{method: http.MethodPut, path: pathObjects + "/" + id, body: `{"value":{"name":"updated"}}`, code: http.StatusOK},
+					{method: http.MethodGet, path: pathObjects + "/" + id, code: http.StatusTooManyRequests},
+					{method: http.MethodGet, path: pathObjects + "/" + id, code: http.StatusOK},
+					{method: http.MethodPut, path: pathObjects + "/" + id + "?validateOnly=true", body: `{"value":{"name":"updated"}}`, code: http.StatusBadRequest},
+				}
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer()
+			defer s.Close()
+
+			id := s.Create("builtin:cool", "", map[string]string{"name": "cool"})
+			for _, f := range tc.faults {
+				s.Inject(f)
+			}
+			for i, st := range tc.steps(id) {
+				if st.token == "" {
+					st.token = Token
+				}
+				if code, body := do(t, s, st.method, st.path, st.token, st.body); code != st.code {
+					t.Errorf("\n%s\nstep %d: %s %s: want status %d, got %d: %s", tc.reason, i, st.method, st.path, st.code, code, body)
+				}
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	want := []string{
+		s.Create("builtin:cool", "", map[string]string{"name": "a"}),
+		s.Create("builtin:cool", "", map[string]string{"name": "b"}),
+		s.Create("builtin:cool", "", map[string]string{"name": "c"}),
+	}
+	s.Create("builtin:other", "", map[string]string{"name": "d"})
+
+	var got []string
+	path := pathObjects + "?schemaIds=builtin:cool&pageSize=2"
+	for path != "" {
+		code, body := do(t, s, http.MethodGet, path, Token, "")
+		if code != http.StatusOK {
+			t.Fatalf("GET %s: want status 200, got %d: %s", path, code, body)
+		}
+		page := listResponse{}
+		if err := json.Unmarshal([]byte(body), &page); err != nil {
+			t.Fatalf("json.Unmarshal(...): %v", err)
+		}
+		for _, o := range page.Items {
+			got = append(got, o.ObjectID)
+		}
+		path = ""
+		if page.NextPageKey != nil {
+			path = pathObjects + "?nextPageKey=" + url.QueryEscape(*page.NextPageKey)
+		}
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("List: -want object IDs, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package settings20
+
+import (
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+)
+
+// An Object is a Settings 2.0 object including its metadata.
+type Object struct {
+	ObjectID      string          `json:"objectId"`
+	SchemaID      string          `json:"schemaId"`
+	SchemaVersion string          `json:"schemaVersion"`
+	Scope         string          `json:"scope"`
+	Author        string          `json:"author,omitempty"`
+	Created       int64           `json:"created,omitempty"`
+	CreatedBy     string          `json:"createdBy,omitempty"`
+	Modified      int64           `json:"modified,omitempty"`
+	ModifiedBy    string          `json:"modifiedBy,omitempty"`
+	UpdateToken   string          `json:"updateToken,omitempty"`
+	Value         json.RawMessage `json:"value"`
+}
+
+// CreatedAt returns the creation time of the object, or nil if unknown.
+func (o *Object) CreatedAt() *time.Time {
+	return fromMillis(o.Created)
+}
+
+// ModifiedAt returns the last modification time of the object, or nil if
+// unknown.
+func (o *Object) ModifiedAt() *time.Time {
+	return fromMillis(o.Modified)
+}
+
+// Observation returns the metadata of the object in the form reported by the
+// status of managed resources.
+func (o *Object) Observation() apisv1alpha1.SettingsObjectObservation {
+	obs := apisv1alpha1.SettingsObjectObservation{
+		SchemaID:      o.SchemaID,
+		SchemaVersion: o.SchemaVersion,
+		Scope:         o.Scope,
+		CreatedBy:     o.CreatedBy,
+		ModifiedBy:    o.ModifiedBy,
+		UpdateToken:   o.UpdateToken,
+	}
+	if t := o.CreatedAt(); t != nil {
+		obs.CreatedAt = &metav1.Time{Time: *t}
+	}
+	if t := o.ModifiedAt(); t != nil {
+		obs.ModifiedAt = &metav1.Time{Time: *t}
+	}
+	return obs
+}
+
+func fromMillis(ms int64) *time.Time {
+	if ms == 0 {
+		return nil
+	}
+	t := time.UnixMilli(ms).UTC()
+	return &t
+}
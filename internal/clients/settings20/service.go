@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package settings20
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
+	"github.com/pkg/errors"
+)
+
+const errUnmarshalValue = "cannot unmarshal settings object value"
+
+// A CRUDService manages the settings objects of a single schema.
+type CRUDService[T settings.Settings] interface {
+	settings.CRUDService[T]
+
+	// GetObject reads the value of the settings object with the supplied ID
+	// into v and returns the object's metadata.
+	GetObject(id string, v T) (*Object, error)
+
+	// UpdateObject replaces the value of the settings object with the
+	// supplied ID, unless it was modified since updateToken was observed.
+	UpdateObject(id string, v T, updateToken string) error
+
+	// Validate validates v without writing it, as the new value of the
+	// settings object with the supplied ID or, if the ID is empty, as a new
+	// settings object.
+	Validate(id string, v T) error
+}
+
+// WithContext returns a settings service that calls the supplied service
+// within the supplied context, if the service supports it. Services that do
+// not, e.g. fakes, are returned as is.
+func WithContext[T settings.Settings](ctx context.Context, svc CRUDService[T]) CRUDService[T] {
+	if c, ok := svc.(interface {
+		WithContext(ctx context.Context) CRUDService[T]
+	}); ok {
+		return c.WithContext(ctx)
+	}
+	return svc
+}
+
+// NewService returns a CRUDService that manages the settings objects of the
+// supplied schema using the supplied Client.
+func NewService[T settings.Settings](schemaID string, c *Client, o ...ServiceOption[T]) CRUDService[T] {
+	s := &service[T]{schemaID: schemaID, client: c}
+	for _, fn := range o {
+		fn(s)
+	}
+	return s
+}
+
+// A ServiceOption configures a CRUDService.
+type ServiceOption[T settings.Settings] func(*service[T])
+
+// WithFilter configures a CRUDService to list only the settings objects whose
+// value passes the supplied filter, e.g. the problem notifications of one
+// type.
+func WithFilter[T settings.Settings](fn func(v T) bool) ServiceOption[T] {
+	return func(s *service[T]) {
+		s.filter = fn
+	}
+}
+
+type service[T settings.Settings] struct {
+	schemaID string
+	client   *Client
+	filter   func(v T) bool
+}
+
+// WithContext returns a copy of the service whose Client sends its requests
+// within the supplied context.
+func (s *service[T]) WithContext(ctx context.Context) CRUDService[T] {
+	cp := *s
+	cp.client = s.client.WithContext(ctx)
+	return &cp
+}
+
+// SchemaID returns the ID of the schema of the settings objects.
+func (s *service[T]) SchemaID() string {
+	return s.schemaID
+}
+
+// Name returns the ID of the schema of the settings objects.
+func (s *service[T]) Name() string {
+	return s.schemaID
+}
+
+// List returns a stub of every settings object of the schema that passes the
+// filter, if any. Objects without a name are skipped, like the upstream
+// Dynatrace client does.
+func (s *service[T]) List() (api.Stubs, error) {
+	objects, err := s.client.Objects(s.schemaID)
+	if err != nil {
+		return nil, err
+	}
+	stubs := api.Stubs{}
+	for _, o := range objects {
+		v := settings.NewSettings[T](s)
+		if err := json.Unmarshal(o.Value, v); err != nil {
+			return nil, errors.Wrap(err, errUnmarshalValue)
+		}
+		settings.SetScope(v, o.Scope)
+		if s.filter != nil && !s.filter(v) {
+			continue
+		}
+		name := settings.Name(v, o.ObjectID)
+		if name == "" {
+			continue
+		}
+		stubs = append(stubs, &api.Stub{ID: o.ObjectID, Name: name, Value: v})
+	}
+	return stubs, nil
+}
+
+// Get reads the settings object with the supplied ID into v.
+func (s *service[T]) Get(id string, v T) error {
+	_, err := s.GetObject(id, v)
+	return err
+}
+
+// GetObject reads the settings object with the supplied ID into v and
+// returns its metadata.
+func (s *service[T]) GetObject(id string, v T) (*Object, error) {
+	o, err := s.client.Object(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(o.Value, v); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalValue)
+	}
+	return o, nil
+}
+
+// Create creates a settings object with the value v in the scope of v.
+func (s *service[T]) Create(v T) (*api.Stub, error) {
+	id, err := s.client.CreateObject(s.schemaID, settings.GetScope(v), v)
+	if err != nil {
+		return nil, err
+	}
+	return &api.Stub{ID: id, Name: settings.Name(v, id)}, nil
+}
+
+// Update replaces the value of the settings object with the supplied ID
+// regardless of its version.
+func (s *service[T]) Update(id string, v T) error {
+	return s.UpdateObject(id, v, "")
+}
+
+// UpdateObject replaces the value of the settings object with the supplied
+// ID, unless it was modified since updateToken was observed.
+func (s *service[T]) UpdateObject(id string, v T, updateToken string) error {
+	return s.client.UpdateObject(id, v, updateToken)
+}
+
+// Delete deletes the settings object with the supplied ID.
+func (s *service[T]) Delete(id string) error {
+	return s.client.DeleteObject(id)
+}
+
+// Validate validates v without writing it, as the new value of the settings
+// object with the supplied ID or, if the ID is empty, as a new settings
+// object.
+func (s *service[T]) Validate(id string, v T) error {
+	if id == "" {
+		return s.client.ValidateCreate(s.schemaID, settings.GetScope(v), v)
+	}
+	return s.client.ValidateUpdate(id, v)
+}
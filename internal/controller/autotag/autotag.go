@@ -17,216 +17,82 @@ limitations under the License.
 package autotag
 
 import (
-	"context"
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/meta"
-	"github.com/crossplane/provider-dynatrace/internal/credentials"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
 	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging"
 	autotaggingservice "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging/settings"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
-	"net/http"
-
-	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
-	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
-	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
-	"github.com/crossplane/provider-dynatrace/internal/features"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+	"github.com/crossplane/provider-dynatrace/internal/credentials"
+	"github.com/crossplane/provider-dynatrace/internal/drift"
 )
 
-const (
-	errNotAutoTag   = "managed resource is not a AutoTag custom resource"
-	errTrackPCUsage = "cannot track ProviderConfig usage"
-	errGetPC        = "cannot get ProviderConfig"
-	errGetCreds     = "cannot get credentials"
-
-	errNewClient = "cannot create new Service"
-)
+// kind maps AutoTag managed resources to automatically applied tag settings
+// objects.
+var kind = &generic.Kind[*v1alpha1.AutoTag, autotaggingservice.Settings]{
+	GroupKind:        v1alpha1.AutoTagGroupKind,
+	GroupVersionKind: v1alpha1.AutoTagGroupVersionKind,
+	Name:             func(cr *v1alpha1.AutoTag) string { return cr.Spec.ForProvider.Name },
+	ToDTO: func(cr *v1alpha1.AutoTag) (autotaggingservice.Settings, error) {
+		return crdToDto(cr.Spec.ForProvider)
+	},
+	LateInitialize: func(cr *v1alpha1.AutoTag, s autotaggingservice.Settings) bool {
+		return lateInitialize(&cr.Spec.ForProvider, s)
+	},
+	Observe: func(cr *v1alpha1.AutoTag, id string, o *settings20.Object, s autotaggingservice.Settings) error {
+		cr.Status.AtProvider = generateObservation(id, o, s)
+		return nil
+	},
+	Validate: func(cr *v1alpha1.AutoTag) field.ErrorList {
+		return validate(cr.Spec.ForProvider)
+	},
+	Spec: func(cr *v1alpha1.AutoTag) *apisv1alpha1.SettingsObjectSpec {
+		return &cr.Spec.SettingsObjectSpec
+	},
+	Metadata: func(cr *v1alpha1.AutoTag) *apisv1alpha1.SettingsObjectObservation {
+		return &cr.Status.AtProvider.SettingsObjectObservation
+	},
+	FromDTO: func(s autotaggingservice.Settings) (*v1alpha1.AutoTag, []string, error) {
+		return &v1alpha1.AutoTag{Spec: v1alpha1.AutoTagSpec{ForProvider: dtoToCrd(s)}}, nil, nil
+	},
+	// Rules and their conditions are sets in the auto-tagging schema.
+	DiffOptions: []cmp.Option{
+		drift.Unordered[*autotaggingservice.Rule](),
+		drift.Unordered[*autotaggingservice.AttributeCondition](),
+	},
+}
 
-func newService(data []byte) (settings.CRUDService[*autotaggingservice.Settings], error) {
+func newService(data []byte) (settings20.CRUDService[*autotaggingservice.Settings], error) {
 	c, err := credentials.Unmarshal(data)
 	if err != nil {
 		return nil, err
 	}
 
-	return autotagging.Service(c), nil
+	return settings20.NewService[*autotaggingservice.Settings](autotagging.SchemaID, settings20.NewClient(c)), nil
 }
 
 // Setup adds a controller that reconciles AutoTag managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
-	name := managed.ControllerName(v1alpha1.AutoTagGroupKind)
-
-	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
-	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
-		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
-	}
-
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.AutoTagGroupVersionKind),
-		managed.WithExternalConnecter(&connector{
-			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newService}),
-		managed.WithLogger(o.Logger.WithValues("controller", name)),
-		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithConnectionPublishers(cps...))
-
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.AutoTag{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+	return generic.Setup(mgr, o, kind, newService)
 }
 
-// A connector is expected to produce an ExternalClient when its Connect method
-// is called.
-type connector struct {
-	kube         client.Client
-	usage        resource.Tracker
-	newServiceFn func(creds []byte) (settings.CRUDService[*autotaggingservice.Settings], error)
+// Exporter returns an Exporter of automatically applied tags.
+func Exporter() generic.Exporter {
+	return generic.NewExporter(kind, newService)
 }
 
-// Connect typically produces an ExternalClient by:
-// 1. Tracking that the managed resource is using a ProviderConfig.
-// 2. Getting the managed resource's ProviderConfig.
-// 3. Getting the credentials specified by the ProviderConfig.
-// 4. Using the credentials to form a client.
-func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*v1alpha1.AutoTag)
-	if !ok {
-		return nil, errors.New(errNotAutoTag)
-	}
-
-	if err := c.usage.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackPCUsage)
-	}
-
-	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetPC)
-	}
-
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
-	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
-	}
-
-	svc, err := c.newServiceFn(data)
-	if err != nil {
-		return nil, errors.Wrap(err, errNewClient)
-	}
-
-	return &external{client: svc}, nil
+// Planner returns a Planner of automatically applied tags.
+func Planner() generic.Planner {
+	return generic.NewPlanner(kind, newService)
 }
 
-// An ExternalClient observes, then either creates, updates, or deletes an
-// external resource to ensure it reflects the managed resource's desired state.
-type external struct {
-	// A 'client' used to connect to the external resource API. In practice this
-	// would be something like an AWS SDK client.
-	client settings.CRUDService[*autotaggingservice.Settings]
-}
-
-func (c *external) Observe(_ context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.AutoTag)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotAutoTag)
-	}
-
-	id := meta.GetExternalName(cr)
-	var autosettings autotaggingservice.Settings
-	err := c.client.Get(id, &autosettings)
-	if err != nil {
-		var restError rest.Error
-		if errors.As(err, &restError) {
-			if restError.Code == http.StatusNotFound {
-				return managed.ExternalObservation{ResourceExists: false}, nil
-			}
-		}
-
-		return managed.ExternalObservation{}, err
-	}
-
-	cr.Status.SetConditions(xpv1.Available())
-
-	local := crdToDto(cr.Spec.ForProvider)
-	if diff := cmp.Diff(autosettings, local, cmpopts.IgnoreFields(notifications.Notification{}, "LegacyID"), cmpopts.EquateEmpty()); diff != "" {
-
-		return managed.ExternalObservation{
-			ResourceExists:   true,
-			ResourceUpToDate: false,
-			Diff:             diff,
-		}, nil
-	}
-
-	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
-	}, nil
-}
-
-func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.AutoTag)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotAutoTag)
-	}
-
-	cr.Status.SetConditions(xpv1.Creating())
-
-	n := crdToDto(cr.Spec.ForProvider)
-	apiResp, err := c.client.Create(&n)
-	if err != nil {
-		return managed.ExternalCreation{}, err
-	}
-
-	meta.SetExternalName(cr, apiResp.ID)
-
-	return managed.ExternalCreation{}, nil
-}
-
-func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*v1alpha1.AutoTag)
-	if !ok {
-		return managed.ExternalUpdate{}, errors.New(errNotAutoTag)
-	}
-
-	id := meta.GetExternalName(cr)
-	n := crdToDto(cr.Spec.ForProvider)
-	err := c.client.Update(id, &n)
-	if err != nil {
-		return managed.ExternalUpdate{}, err
-	}
-
-	return managed.ExternalUpdate{}, nil
-}
-
-func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.AutoTag)
-	if !ok {
-		return errors.New(errNotAutoTag)
-	}
-
-	err := c.client.Delete(meta.GetExternalName(cr))
-	if err != nil {
-		return err
-	}
-
-	cr.Status.SetConditions(xpv1.Deleting())
-	return nil
+// SetupWebhook adds a webhook that validates AutoTag managed resources.
+func SetupWebhook(mgr ctrl.Manager) error {
+	return generic.SetupWebhook(mgr, kind)
 }
@@ -18,21 +18,27 @@ package autotag
 
 import (
 	"context"
-	"github.com/crossplane/crossplane-runtime/pkg/meta"
-	"github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api"
-	autotaggingservice "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging/settings"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"net/http"
 	"testing"
 
-	"github.com/google/go-cmp/cmp"
-
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging"
+	autotaggingservice "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging/settings"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20/fake"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic/generictest"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -44,8 +50,9 @@ import (
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
 type mockClient struct {
-	list func() (api.Stubs, error)
-	get  func(id string, v *autotaggingservice.Settings) error
+	list   func() (api.Stubs, error)
+	get    func(id string, v *autotaggingservice.Settings) error
+	update func(id string, v *autotaggingservice.Settings, updateToken string) error
 }
 
 func (m mockClient) List() (api.Stubs, error) {
@@ -56,6 +63,13 @@ func (m mockClient) Get(id string, v *autotaggingservice.Settings) error {
 	return m.get(id, v)
 }
 
+func (m mockClient) GetObject(id string, v *autotaggingservice.Settings) (*settings20.Object, error) {
+	if err := m.get(id, v); err != nil {
+		return nil, err
+	}
+	return &settings20.Object{ObjectID: id}, nil
+}
+
 func (m mockClient) SchemaID() string {
 	panic("not used")
 }
@@ -68,6 +82,14 @@ func (m mockClient) Update(_ string, _ *autotaggingservice.Settings) error {
 	panic("not used")
 }
 
+func (m mockClient) UpdateObject(id string, v *autotaggingservice.Settings, updateToken string) error {
+	return m.update(id, v, updateToken)
+}
+
+func (m mockClient) Validate(_ string, _ *autotaggingservice.Settings) error {
+	panic("not used")
+}
+
 func (m mockClient) Delete(_ string) error {
 	panic("not used")
 }
@@ -76,11 +98,11 @@ func (m mockClient) Name() string {
 	panic("not used")
 }
 
-var _ settings.CRUDService[*autotaggingservice.Settings] = mockClient{}
+var _ settings20.CRUDService[*autotaggingservice.Settings] = mockClient{}
 
 func TestObserve(t *testing.T) {
 	type fields struct {
-		service mockClient
+		service settings20.CRUDService[*autotaggingservice.Settings]
 	}
 
 	type args struct {
@@ -111,7 +133,7 @@ func TestObserve(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: nil,
+				ctx: context.Background(),
 				mg: &v1alpha1.AutoTag{
 					ObjectMeta: v1.ObjectMeta{
 						Annotations: map[string]string{
@@ -127,11 +149,51 @@ func TestObserve(t *testing.T) {
 				err: nil,
 			},
 		},
+		"SuccessImportByName": {
+			reason: "We should adopt an existing object with a matching name if asked to import by name",
+			fields: fields{
+				service: mockClient{
+					list: func() (api.Stubs, error) {
+						return api.Stubs{{ID: "existing-id", Name: "cool-tag"}}, nil
+					},
+					get: func(id string, v *autotaggingservice.Settings) error {
+						if id != "existing-id" {
+							return rest.Error{Code: http.StatusNotFound}
+						}
+						v.Name = "cool-tag"
+						return nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: &v1alpha1.AutoTag{
+					ObjectMeta: v1.ObjectMeta{
+						Name: "cool",
+						Annotations: map[string]string{
+							meta.AnnotationKeyExternalName:         "cool",
+							apisv1alpha1.AnnotationKeyImportByName: "true",
+						},
+					},
+					Spec: v1alpha1.AutoTagSpec{
+						ForProvider: v1alpha1.AutoTagParameters{Name: "cool-tag"},
+					},
+				},
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+				err: nil,
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{client: tc.fields.service}
+			e := generic.NewExternal(kind, tc.fields.service)
 			got, err := e.Observe(tc.args.ctx, tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -142,3 +204,52 @@ func TestObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcile(t *testing.T) {
+	env := generictest.New(t, kind, newService)
+	description := "observed"
+	existing := env.API.Create(autotagging.SchemaID, fake.ScopeEnvironment, &autotaggingservice.Settings{
+		Name:        "cool-tag",
+		Description: &description,
+		Rules:       autotaggingservice.Rules{},
+	})
+
+	cr := &v1alpha1.AutoTag{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "cool",
+			Annotations: map[string]string{apisv1alpha1.AnnotationKeyImportByName: "true"},
+		},
+		Spec: v1alpha1.AutoTagSpec{ForProvider: v1alpha1.AutoTagParameters{Name: "cool-tag"}},
+	}
+	cr.SetDeletionPolicy(xpv1.DeletionOrphan)
+	env.Create(t, cr)
+
+	// The existing auto-tag is adopted by name rather than created, and its
+	// description late-initialized.
+	env.Reconcile(t, cr)
+	if got := meta.GetExternalName(cr); got != existing {
+		t.Fatalf("Reconcile(...): want external name %q of the existing auto-tag, got %q", existing, got)
+	}
+	if got := env.API.Objects(autotagging.SchemaID); len(got) != 1 {
+		t.Errorf("Reconcile(...): want the existing auto-tag only, got %d auto-tags", len(got))
+	}
+	if diff := cmp.Diff(&description, cr.Spec.ForProvider.Description); diff != "" {
+		t.Errorf("Reconcile(...): -want description, +got:\n%s\n", diff)
+	}
+
+	env.Reconcile(t, cr)
+	generictest.CheckCondition(t, cr, xpv1.Available())
+	generictest.CheckCondition(t, cr, xpv1.ReconcileSuccess())
+	if got := cr.Status.AtProvider.CreatedBy; got == fake.Author {
+		t.Errorf("Reconcile(...): status.atProvider.createdBy: want the author of the existing auto-tag, got %q", got)
+	}
+
+	// An orphaned auto-tag outlives its managed resource.
+	env.Delete(t, cr)
+	if env.Reconcile(t, cr) {
+		t.Errorf("Reconcile(...): managed resource of orphaned auto-tag still exists")
+	}
+	if _, ok := env.API.Object(existing); !ok {
+		t.Errorf("Reconcile(...): orphaned auto-tag was deleted")
+	}
+}
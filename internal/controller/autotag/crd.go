@@ -1,20 +1,30 @@
 package autotag
 
 import (
+	"github.com/pkg/errors"
+
 	"github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/lateinit"
 	autotagging "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging/settings"
 )
 
-func crdToDto(v v1alpha1.AutoTagParameters) autotagging.Settings {
+const errFmtNoAppliesTo = "spec.forProvider.rules[%d].appliesTo is required for rules of type ME"
+
+func crdToDto(v v1alpha1.AutoTagParameters) (autotagging.Settings, error) {
+	rules, err := convertRules(v.Rules)
+	if err != nil {
+		return autotagging.Settings{}, err
+	}
 
 	return autotagging.Settings{
 		Description: v.Description,
 		Name:        v.Name,
-		Rules:       convertRules(v.Rules),
-	}
+		Rules:       rules,
+	}, nil
 }
 
-func convertRules(rules []v1alpha1.Rule) autotagging.Rules {
+func convertRules(rules []v1alpha1.Rule) (autotagging.Rules, error) {
 	result := make(autotagging.Rules, len(rules))
 
 	for i, r := range rules {
@@ -27,6 +37,9 @@ func convertRules(rules []v1alpha1.Rule) autotagging.Rules {
 		}
 
 		if rule.Type == autotagging.RuleTypes.Me {
+			if r.AppliesTo == nil {
+				return nil, errors.Errorf(errFmtNoAppliesTo, i)
+			}
 			rule.AttributeRule = &autotagging.AutoTagAttributeRule{
 				EntityType:                autotagging.AutoTagMeType(*r.AppliesTo),
 				Conditions:                convertConditions(r.Conditions),
@@ -43,7 +56,7 @@ func convertRules(rules []v1alpha1.Rule) autotagging.Rules {
 		result[i] = &rule
 	}
 
-	return result
+	return result, nil
 }
 
 func convertConditions(conditions []v1alpha1.Condition) autotagging.AttributeConditions {
@@ -66,3 +79,195 @@ func convertConditions(conditions []v1alpha1.Condition) autotagging.AttributeCon
 
 	return result
 }
+
+// dtoToCrd returns the parameters that desire the supplied settings.
+func dtoToCrd(s autotagging.Settings) v1alpha1.AutoTagParameters {
+	return v1alpha1.AutoTagParameters{
+		Name:        s.Name,
+		Description: s.Description,
+		Rules:       dtoToRules(s.Rules),
+	}
+}
+
+// generateObservation returns the observed state of the supplied settings.
+func generateObservation(id string, o *settings20.Object, s autotagging.Settings) v1alpha1.AutoTagObservation {
+	return v1alpha1.AutoTagObservation{
+		ID:                        id,
+		SettingsObjectObservation: o.Observation(),
+		Name:                      s.Name,
+		Description:               s.Description,
+		Rules:                     dtoToRules(s.Rules),
+	}
+}
+
+func dtoToRules(rules autotagging.Rules) []v1alpha1.Rule {
+	if rules == nil {
+		return nil
+	}
+
+	result := make([]v1alpha1.Rule, 0, len(rules))
+
+	for _, r := range rules {
+		if r == nil {
+			continue
+		}
+
+		rule := v1alpha1.Rule{
+			Type:                  string(r.Type),
+			Enabled:               r.Enabled,
+			EntitySelector:        r.EntitySelector,
+			Value:                 r.ValueFormat,
+			TagValueNormalization: string(r.ValueNormalization),
+		}
+
+		if a := r.AttributeRule; a != nil {
+			appliesTo := string(a.EntityType)
+			rule.AppliesTo = &appliesTo
+			rule.Conditions = dtoToConditions(a.Conditions)
+			rule.AzureToPgPropagation = a.AzureToPGPropagation
+			rule.AzureToServicePropagation = a.AzureToServicePropagation
+			rule.HostToPgPropagation = a.HostToPGPropagation
+			rule.PgToHostPropagation = a.PGToHostPropagation
+			rule.PgToServicePropagation = a.PGToServicePropagation
+			rule.ServiceToHostPropagation = a.ServiceToHostPropagation
+			rule.ServiceToPGPropagation = a.ServiceToPGPropagation
+		}
+
+		result = append(result, rule)
+	}
+
+	return result
+}
+
+func dtoToConditions(conditions autotagging.AttributeConditions) []v1alpha1.Condition {
+	if conditions == nil {
+		return nil
+	}
+
+	result := make([]v1alpha1.Condition, 0, len(conditions))
+
+	for _, c := range conditions {
+		if c == nil {
+			continue
+		}
+
+		result = append(result, v1alpha1.Condition{
+			CaseSensitive:    c.CaseSensitive,
+			DynamicKey:       c.DynamicKey,
+			DynamicKeySource: c.DynamicKeySource,
+			EntityId:         c.EntityID,
+			EnumValue:        c.EnumValue,
+			IntegerValue:     c.IntegerValue,
+			Property:         string(c.Key),
+			Operator:         string(c.Operator),
+			StringValue:      c.StringValue,
+			Tag:              c.Tag,
+		})
+	}
+
+	return result
+}
+
+// lateInitialize fills unset optional parameters from the observed settings.
+// Rules and conditions are sets, so each desired rule and condition is
+// late-initialized from the observed one that matches all of its set
+// parameters, regardless of their order.
+func lateInitialize(in *v1alpha1.AutoTagParameters, s autotagging.Settings) bool {
+	li := &lateinit.Tracker{}
+	lateinit.Ptr(li, &in.Description, s.Description)
+
+	for i, r := range lateinit.Match(in.Rules, s.Rules, matchesRule) {
+		if r != nil {
+			lateInitializeRule(li, &in.Rules[i], r)
+		}
+	}
+
+	return li.Changed()
+}
+
+func lateInitializeRule(li *lateinit.Tracker, in *v1alpha1.Rule, r *autotagging.Rule) {
+	lateinit.Ptr(li, &in.Value, r.ValueFormat)
+	lateinit.Ptr(li, &in.EntitySelector, r.EntitySelector)
+
+	a := r.AttributeRule
+	if a == nil {
+		return
+	}
+
+	lateinit.String(li, &in.AppliesTo, a.EntityType)
+	lateinit.Ptr(li, &in.AzureToPgPropagation, a.AzureToPGPropagation)
+	lateinit.Ptr(li, &in.AzureToServicePropagation, a.AzureToServicePropagation)
+	lateinit.Ptr(li, &in.HostToPgPropagation, a.HostToPGPropagation)
+	lateinit.Ptr(li, &in.PgToHostPropagation, a.PGToHostPropagation)
+	lateinit.Ptr(li, &in.PgToServicePropagation, a.PGToServicePropagation)
+	lateinit.Ptr(li, &in.ServiceToHostPropagation, a.ServiceToHostPropagation)
+	lateinit.Ptr(li, &in.ServiceToPGPropagation, a.ServiceToPGPropagation)
+
+	for i, c := range lateinit.Match(in.Conditions, a.Conditions, matchesCondition) {
+		if c == nil {
+			continue
+		}
+		lateinit.Ptr(li, &in.Conditions[i].CaseSensitive, c.CaseSensitive)
+		lateinit.Ptr(li, &in.Conditions[i].DynamicKey, c.DynamicKey)
+		lateinit.Ptr(li, &in.Conditions[i].DynamicKeySource, c.DynamicKeySource)
+		lateinit.Ptr(li, &in.Conditions[i].EntityId, c.EntityID)
+		lateinit.Ptr(li, &in.Conditions[i].EnumValue, c.EnumValue)
+		lateinit.Ptr(li, &in.Conditions[i].IntegerValue, c.IntegerValue)
+		lateinit.Ptr(li, &in.Conditions[i].StringValue, c.StringValue)
+		lateinit.Ptr(li, &in.Conditions[i].Tag, c.Tag)
+	}
+}
+
+// matchesRule returns true if the observed rule matches all set parameters
+// of the desired rule, including all of its conditions.
+func matchesRule(in *v1alpha1.Rule, r *autotagging.Rule) bool {
+	if r == nil || autotagging.RuleType(in.Type) != r.Type || in.Enabled != r.Enabled {
+		return false
+	}
+	if in.TagValueNormalization != "" && autotagging.Normalization(in.TagValueNormalization) != r.ValueNormalization {
+		return false
+	}
+	if !lateinit.Equal(in.Value, r.ValueFormat) || !lateinit.Equal(in.EntitySelector, r.EntitySelector) {
+		return false
+	}
+
+	a := r.AttributeRule
+	if a == nil {
+		return in.AppliesTo == nil && len(in.Conditions) == 0
+	}
+	if in.AppliesTo != nil && autotagging.AutoTagMeType(*in.AppliesTo) != a.EntityType {
+		return false
+	}
+	if len(in.Conditions) != len(a.Conditions) {
+		return false
+	}
+	for _, c := range lateinit.Match(in.Conditions, a.Conditions, matchesCondition) {
+		if c == nil {
+			return false
+		}
+	}
+
+	return lateinit.Equal(in.AzureToPgPropagation, a.AzureToPGPropagation) &&
+		lateinit.Equal(in.AzureToServicePropagation, a.AzureToServicePropagation) &&
+		lateinit.Equal(in.HostToPgPropagation, a.HostToPGPropagation) &&
+		lateinit.Equal(in.PgToHostPropagation, a.PGToHostPropagation) &&
+		lateinit.Equal(in.PgToServicePropagation, a.PGToServicePropagation) &&
+		lateinit.Equal(in.ServiceToHostPropagation, a.ServiceToHostPropagation) &&
+		lateinit.Equal(in.ServiceToPGPropagation, a.ServiceToPGPropagation)
+}
+
+// matchesCondition returns true if the observed condition matches all set
+// parameters of the desired condition.
+func matchesCondition(in *v1alpha1.Condition, c *autotagging.AttributeCondition) bool {
+	return c != nil &&
+		autotagging.Attribute(in.Property) == c.Key &&
+		autotagging.Operator(in.Operator) == c.Operator &&
+		lateinit.Equal(in.CaseSensitive, c.CaseSensitive) &&
+		lateinit.Equal(in.DynamicKey, c.DynamicKey) &&
+		lateinit.Equal(in.DynamicKeySource, c.DynamicKeySource) &&
+		lateinit.Equal(in.EntityId, c.EntityID) &&
+		lateinit.Equal(in.EnumValue, c.EnumValue) &&
+		lateinit.Equal(in.IntegerValue, c.IntegerValue) &&
+		lateinit.Equal(in.StringValue, c.StringValue) &&
+		lateinit.Equal(in.Tag, c.Tag)
+}
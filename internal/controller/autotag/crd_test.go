@@ -0,0 +1,376 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autotag
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	autotagging "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging/settings"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+)
+
+func TestLateInitialize(t *testing.T) {
+	description := "observed"
+	appliesTo := "HOST"
+	selector := "type(HOST)"
+	value := "{Host:DetectedName}"
+	tag := "env:prod"
+	yes, no := true, false
+
+	observed := autotagging.Settings{
+		Name:        "cool-tag",
+		Description: &description,
+		Rules: autotagging.Rules{
+			{
+				Type: autotagging.RuleTypes.Me,
+				AttributeRule: &autotagging.AutoTagAttributeRule{
+					EntityType:          autotagging.AutoTagMeType(appliesTo),
+					HostToPGPropagation: &yes,
+					Conditions: autotagging.AttributeConditions{
+						{CaseSensitive: &yes},
+					},
+				},
+			},
+		},
+	}
+
+	type args struct {
+		in v1alpha1.AutoTagParameters
+		s  autotagging.Settings
+	}
+
+	type want struct {
+		out     v1alpha1.AutoTagParameters
+		changed bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FillUnset": {
+			reason: "Unset optional parameters should be filled from the observed settings.",
+			args: args{
+				in: v1alpha1.AutoTagParameters{
+					Name:  "cool-tag",
+					Rules: []v1alpha1.Rule{{Type: "ME", Conditions: []v1alpha1.Condition{{}}}},
+				},
+				s: observed,
+			},
+			want: want{
+				out: v1alpha1.AutoTagParameters{
+					Name:        "cool-tag",
+					Description: &description,
+					Rules: []v1alpha1.Rule{{
+						Type:                "ME",
+						AppliesTo:           &appliesTo,
+						HostToPgPropagation: &yes,
+						Conditions:          []v1alpha1.Condition{{CaseSensitive: &yes}},
+					}},
+				},
+				changed: true,
+			},
+		},
+		"KeepSet": {
+			reason: "Parameters that are already set should never be overwritten.",
+			args: args{
+				in: v1alpha1.AutoTagParameters{
+					Name:        "cool-tag",
+					Description: &description,
+					Rules: []v1alpha1.Rule{{
+						Type:                "ME",
+						AppliesTo:           &appliesTo,
+						HostToPgPropagation: &no,
+						Conditions:          []v1alpha1.Condition{{CaseSensitive: &no}},
+					}},
+				},
+				s: observed,
+			},
+			want: want{
+				out: v1alpha1.AutoTagParameters{
+					Name:        "cool-tag",
+					Description: &description,
+					Rules: []v1alpha1.Rule{{
+						Type:                "ME",
+						AppliesTo:           &appliesTo,
+						HostToPgPropagation: &no,
+						Conditions:          []v1alpha1.Condition{{CaseSensitive: &no}},
+					}},
+				},
+			},
+		},
+		"RuleTypeMismatch": {
+			reason: "Rules should not be late-initialized from an observed rule of another type.",
+			args: args{
+				in: v1alpha1.AutoTagParameters{
+					Name:        "cool-tag",
+					Description: &description,
+					Rules:       []v1alpha1.Rule{{Type: "SELECTOR"}},
+				},
+				s: observed,
+			},
+			want: want{
+				out: v1alpha1.AutoTagParameters{
+					Name:        "cool-tag",
+					Description: &description,
+					Rules:       []v1alpha1.Rule{{Type: "SELECTOR"}},
+				},
+			},
+		},
+		"Reordered": {
+			reason: "Rules and conditions should be late-initialized from the matching observed ones regardless of their order.",
+			args: args{
+				in: v1alpha1.AutoTagParameters{
+					Name:        "cool-tag",
+					Description: &description,
+					Rules: []v1alpha1.Rule{
+						{Type: "SELECTOR", EntitySelector: &selector},
+						{Type: "ME", Conditions: []v1alpha1.Condition{{Property: "HOST_NAME"}, {Property: "HOST_TAGS"}}},
+					},
+				},
+				s: autotagging.Settings{
+					Name: "cool-tag",
+					Rules: autotagging.Rules{
+						{
+							Type: autotagging.RuleTypes.Me,
+							AttributeRule: &autotagging.AutoTagAttributeRule{
+								EntityType: autotagging.AutoTagMeType(appliesTo),
+								Conditions: autotagging.AttributeConditions{
+									{Key: "HOST_TAGS", Tag: &tag},
+									{Key: "HOST_NAME", CaseSensitive: &yes},
+								},
+							},
+						},
+						{Type: autotagging.RuleTypes.Selector, EntitySelector: &selector, ValueFormat: &value},
+					},
+				},
+			},
+			want: want{
+				out: v1alpha1.AutoTagParameters{
+					Name:        "cool-tag",
+					Description: &description,
+					Rules: []v1alpha1.Rule{
+						{Type: "SELECTOR", EntitySelector: &selector, Value: &value},
+						{Type: "ME", AppliesTo: &appliesTo, Conditions: []v1alpha1.Condition{
+							{Property: "HOST_NAME", CaseSensitive: &yes},
+							{Property: "HOST_TAGS", Tag: &tag},
+						}},
+					},
+				},
+				changed: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed := lateInitialize(&tc.args.in, tc.args.s)
+			if diff := cmp.Diff(tc.want.out, tc.args.in); diff != "" {
+				t.Errorf("\n%s\nlateInitialize(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changed, changed); diff != "" {
+				t.Errorf("\n%s\nlateInitialize(...): -want changed, +got changed:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCrdToDto(t *testing.T) {
+	selector := "type(HOST)"
+
+	type want struct {
+		s   autotagging.Settings
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.AutoTagParameters
+		want   want
+	}{
+		"NilOptionals": {
+			reason: "Unset optional parameters should be converted to unset properties.",
+			in:     v1alpha1.AutoTagParameters{Name: "cool-tag"},
+			want:   want{s: autotagging.Settings{Name: "cool-tag", Rules: autotagging.Rules{}}},
+		},
+		"SelectorWithoutAppliesTo": {
+			reason: "Rules of type SELECTOR do not need the entity type they apply to.",
+			in: v1alpha1.AutoTagParameters{
+				Name:  "cool-tag",
+				Rules: []v1alpha1.Rule{{Type: "SELECTOR", EntitySelector: &selector}},
+			},
+			want: want{s: autotagging.Settings{Name: "cool-tag", Rules: autotagging.Rules{
+				{Type: autotagging.RuleTypes.Selector, EntitySelector: &selector},
+			}}},
+		},
+		"MEWithoutAppliesTo": {
+			reason: "Rules of type ME without the entity type they apply to should return an error rather than panic.",
+			in: v1alpha1.AutoTagParameters{
+				Name:  "cool-tag",
+				Rules: []v1alpha1.Rule{{Type: "SELECTOR", EntitySelector: &selector}, {Type: "ME"}},
+			},
+			want: want{err: errors.Errorf(errFmtNoAppliesTo, 1)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := crdToDto(tc.in)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncrdToDto(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.s, s); diff != "" {
+				t.Errorf("\n%s\ncrdToDto(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	appliesTo := "HOST"
+	selector := "type(HOST)"
+	tag := "env:prod"
+	yes := true
+
+	params := v1alpha1.AutoTagParameters{
+		Name: "cool-tag",
+		Rules: []v1alpha1.Rule{
+			{Type: "SELECTOR", Enabled: true, EntitySelector: &selector},
+			{Type: "ME", Enabled: true, AppliesTo: &appliesTo, Conditions: []v1alpha1.Condition{
+				{Property: "HOST_NAME", Operator: "EXISTS"},
+				{Property: "HOST_TAGS", Operator: "EQUALS", Tag: &tag},
+			}},
+		},
+	}
+
+	// observed returns the declared settings as the API may return them, with
+	// every list reversed and server-side defaults filled in.
+	observed := func(modify ...func(s *autotagging.Settings)) autotagging.Settings {
+		s, err := crdToDto(*params.DeepCopy())
+		if err != nil {
+			t.Fatalf("crdToDto(...): %v", err)
+		}
+		reverse(s.Rules)
+		for _, r := range s.Rules {
+			if r.AttributeRule != nil {
+				reverse(r.AttributeRule.Conditions)
+				r.AttributeRule.HostToPGPropagation = &yes
+			}
+		}
+		for _, fn := range modify {
+			fn(&s)
+		}
+		return s
+	}
+
+	cases := map[string]struct {
+		reason   string
+		observed autotagging.Settings
+		want     []string
+	}{
+		"Reordered": {
+			reason:   "Rules and conditions are sets, so their order should not drift.",
+			observed: observed(),
+		},
+		"Changed": {
+			reason: "A changed condition should drift. Its rule no longer matches the desired one, so its defaults are not late-initialized either.",
+			observed: observed(func(s *autotagging.Settings) {
+				s.Rules[0].AttributeRule.Conditions[1].Operator = "BEGINS_WITH"
+			}),
+			want: []string{"rules.attributeRule.conditions.operator", "rules.attributeRule.hostToPGPropagation"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.AutoTag{Spec: v1alpha1.AutoTagSpec{ForProvider: *params.DeepCopy()}}
+			kind.LateInitialize(cr, tc.observed)
+			desired, err := kind.ToDTO(cr)
+			if err != nil {
+				t.Fatalf("kind.ToDTO(...): %v", err)
+			}
+			got := kind.Compare(cr, tc.observed, desired)
+			if diff := cmp.Diff(tc.want, got.Paths); diff != "" {
+				t.Errorf("\n%s\nkind.Compare(...): -want drifted paths, +got:\n%s\n", tc.reason, diff)
+			}
+
+			// Converting the observed rules back to parameters, e.g. to
+			// report them in status, must not drift either.
+			imported, err := crdToDto(v1alpha1.AutoTagParameters{Name: tc.observed.Name, Rules: dtoToRules(tc.observed.Rules)})
+			if err != nil {
+				t.Fatalf("crdToDto(dtoToRules(...)): %v", err)
+			}
+			if got := kind.Compare(cr, tc.observed, imported); got.Drifted() {
+				t.Errorf("\n%s\ncrdToDto(dtoToRules(...)): drifted in %v:\n%s\n", tc.reason, got.Paths, got.Diff)
+			}
+		})
+	}
+}
+
+func reverse[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+func TestDtoToCrd(t *testing.T) {
+	appliesTo := "HOST"
+	selector := "type(HOST)"
+	description := "Owner of the entity"
+	value := "{Host:DetectedName}"
+	tag := "owner"
+
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.AutoTagParameters
+	}{
+		"NilOptionals": {
+			reason: "Unset optional parameters should be exported as empty rules.",
+			in:     v1alpha1.AutoTagParameters{Name: "cool-tag", Rules: []v1alpha1.Rule{}},
+		},
+		"Rules": {
+			reason: "An exported auto-tag should desire the auto-tag it was exported from.",
+			in: v1alpha1.AutoTagParameters{
+				Name:        "cool-tag",
+				Description: &description,
+				Rules: []v1alpha1.Rule{
+					{Type: "SELECTOR", Enabled: true, EntitySelector: &selector, TagValueNormalization: "Leave text as-is"},
+					{Type: "ME", Enabled: true, Value: &value, AppliesTo: &appliesTo, TagValueNormalization: "To lower case", Conditions: []v1alpha1.Condition{
+						{Property: "HOST_TAGS", Operator: "EQUALS", Tag: &tag},
+					}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := crdToDto(tc.in)
+			if err != nil {
+				t.Fatalf("crdToDto(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.in, dtoToCrd(s)); diff != "" {
+				t.Errorf("\n%s\ndtoToCrd(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
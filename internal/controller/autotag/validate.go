@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autotag
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+)
+
+const (
+	ruleTypeME       = "ME"
+	ruleTypeSelector = "SELECTOR"
+
+	maxConditions = 30
+)
+
+// A propagation is a parameter of a rule that only applies to entities of a
+// single type.
+type propagation struct {
+	entityType string
+	name       string
+	value      *bool
+}
+
+// propagations returns the propagation parameters of the supplied rule.
+func propagations(r *v1alpha1.Rule) []propagation {
+	return []propagation{
+		{entityType: "AZURE", name: "azureToPgPropagation", value: r.AzureToPgPropagation},
+		{entityType: "AZURE", name: "azureToServicePropagation", value: r.AzureToServicePropagation},
+		{entityType: "HOST", name: "hostToPgPropagation", value: r.HostToPgPropagation},
+		{entityType: "PROCESS_GROUP", name: "pgToHostPropagation", value: r.PgToHostPropagation},
+		{entityType: "PROCESS_GROUP", name: "pgToServicePropagation", value: r.PgToServicePropagation},
+		{entityType: "SERVICE", name: "serviceToHostPropagation", value: r.ServiceToHostPropagation},
+		{entityType: "SERVICE", name: "serviceToPGPropagation", value: r.ServiceToPGPropagation},
+	}
+}
+
+// validate returns the constraints of the auto-tagging schema the supplied
+// parameters violate.
+func validate(in v1alpha1.AutoTagParameters) field.ErrorList {
+	p := field.NewPath("spec", "forProvider")
+	errs := field.ErrorList{}
+
+	if strings.TrimSpace(in.Name) == "" {
+		errs = append(errs, field.Required(p.Child("name"), "the name of the tag must not be blank"))
+	}
+
+	for i := range in.Rules {
+		errs = append(errs, validateRule(p.Child("rules").Index(i), &in.Rules[i])...)
+	}
+
+	return errs
+}
+
+func validateRule(p *field.Path, r *v1alpha1.Rule) field.ErrorList {
+	errs := field.ErrorList{}
+
+	switch r.Type {
+	case ruleTypeME:
+		if r.AppliesTo == nil || *r.AppliesTo == "" {
+			errs = append(errs, field.Required(p.Child("appliesTo"), "rules of type ME must specify the entity type they apply to"))
+		}
+		if len(r.Conditions) == 0 {
+			errs = append(errs, field.Required(p.Child("conditions"), "rules of type ME must have at least one condition"))
+		}
+		if len(r.Conditions) > maxConditions {
+			errs = append(errs, field.TooMany(p.Child("conditions"), len(r.Conditions), maxConditions))
+		}
+		if r.EntitySelector != nil {
+			errs = append(errs, field.Forbidden(p.Child("entitySelector"), "only rules of type SELECTOR may specify an entity selector"))
+		}
+	case ruleTypeSelector:
+		if r.EntitySelector == nil || strings.TrimSpace(*r.EntitySelector) == "" {
+			errs = append(errs, field.Required(p.Child("entitySelector"), "rules of type SELECTOR must specify an entity selector"))
+		}
+		if r.AppliesTo != nil {
+			errs = append(errs, field.Forbidden(p.Child("appliesTo"), "only rules of type ME may specify the entity type they apply to"))
+		}
+		if len(r.Conditions) > 0 {
+			errs = append(errs, field.Forbidden(p.Child("conditions"), "only rules of type ME may have conditions"))
+		}
+	}
+
+	appliesTo := ""
+	if r.AppliesTo != nil {
+		appliesTo = *r.AppliesTo
+	}
+	for _, pr := range propagations(r) {
+		if pr.value != nil && pr.entityType != appliesTo {
+			errs = append(errs, field.Forbidden(p.Child(pr.name), "only rules that apply to "+pr.entityType+" entities may specify "+pr.name))
+		}
+	}
+
+	for i, c := range r.Conditions {
+		if c.Property == "" {
+			errs = append(errs, field.Required(p.Child("conditions").Index(i).Child("property"), "conditions must specify the property they test"))
+		}
+	}
+
+	return errs
+}
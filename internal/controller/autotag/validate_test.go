@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autotag
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	host := "HOST"
+	selector := "type(HOST)"
+	yes := true
+	rules := field.NewPath("spec", "forProvider", "rules")
+
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.AutoTagParameters
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "Rules of both types with their required parameters should be valid.",
+			in: v1alpha1.AutoTagParameters{
+				Name: "cool-tag",
+				Rules: []v1alpha1.Rule{
+					{Type: "ME", AppliesTo: &host, HostToPgPropagation: &yes, Conditions: []v1alpha1.Condition{{Property: "HOST_NAME", Operator: "EXISTS"}}},
+					{Type: "SELECTOR", EntitySelector: &selector},
+				},
+			},
+			want: field.ErrorList{},
+		},
+		"MEWithoutAppliesTo": {
+			reason: "Rules of type ME should require the entity type they apply to and at least one condition.",
+			in: v1alpha1.AutoTagParameters{
+				Name:  "cool-tag",
+				Rules: []v1alpha1.Rule{{Type: "ME", EntitySelector: &selector}},
+			},
+			want: field.ErrorList{
+				field.Required(rules.Index(0).Child("appliesTo"), "rules of type ME must specify the entity type they apply to"),
+				field.Required(rules.Index(0).Child("conditions"), "rules of type ME must have at least one condition"),
+				field.Forbidden(rules.Index(0).Child("entitySelector"), "only rules of type SELECTOR may specify an entity selector"),
+			},
+		},
+		"SelectorWithoutEntitySelector": {
+			reason: "Rules of type SELECTOR should require an entity selector.",
+			in: v1alpha1.AutoTagParameters{
+				Name:  "cool-tag",
+				Rules: []v1alpha1.Rule{{Type: "SELECTOR"}},
+			},
+			want: field.ErrorList{
+				field.Required(rules.Index(0).Child("entitySelector"), "rules of type SELECTOR must specify an entity selector"),
+			},
+		},
+		"PropagationOfOtherEntityType": {
+			reason: "Propagation parameters should only be allowed for the entity type they apply to.",
+			in: v1alpha1.AutoTagParameters{
+				Name:  "cool-tag",
+				Rules: []v1alpha1.Rule{{Type: "ME", AppliesTo: &host, PgToHostPropagation: &yes, Conditions: []v1alpha1.Condition{{Property: "HOST_NAME"}}}},
+			},
+			want: field.ErrorList{
+				field.Forbidden(rules.Index(0).Child("pgToHostPropagation"), "only rules that apply to PROCESS_GROUP entities may specify pgToHostPropagation"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := validate(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
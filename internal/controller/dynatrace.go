@@ -18,26 +18,112 @@ package controller
 
 import (
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
-	"github.com/crossplane/provider-dynatrace/internal/controller/autotag"
-	"github.com/crossplane/provider-dynatrace/internal/controller/email"
-	"github.com/crossplane/provider-dynatrace/internal/controller/slack"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	alertingv1alpha1 "github.com/crossplane/provider-dynatrace/apis/alerting/v1alpha1"
+	notificationv1alpha1 "github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
+	tagsv1alpha1 "github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/controller/autotag"
 	"github.com/crossplane/provider-dynatrace/internal/controller/config"
-	profile "github.com/crossplane/provider-dynatrace/internal/controller/profile"
+	"github.com/crossplane/provider-dynatrace/internal/controller/email"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+	"github.com/crossplane/provider-dynatrace/internal/controller/profile"
+	"github.com/crossplane/provider-dynatrace/internal/controller/slack"
 )
 
-// Setup creates all Dynatrace controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
-	for _, setup := range []func(ctrl.Manager, controller.Options) error{
-		config.Setup,
-		profile.Setup,
-		email.Setup,
-		slack.Setup,
-		autotag.Setup,
-	} {
-		if err := setup(mgr, o); err != nil {
+// A kind of Dynatrace managed resource and how to set up its controller and
+// webhook.
+type kind struct {
+	gvk          schema.GroupVersionKind
+	setup        func(ctrl.Manager, controller.Options) error
+	setupWebhook func(ctrl.Manager) error
+}
+
+var kinds = []kind{
+	{gvk: alertingv1alpha1.ProfileGroupVersionKind, setup: profile.Setup, setupWebhook: profile.SetupWebhook},
+	{gvk: notificationv1alpha1.EmailGroupVersionKind, setup: email.Setup, setupWebhook: email.SetupWebhook},
+	{gvk: notificationv1alpha1.SlackGroupVersionKind, setup: slack.Setup, setupWebhook: slack.SetupWebhook},
+	{gvk: tagsv1alpha1.AutoTagGroupVersionKind, setup: autotag.Setup, setupWebhook: autotag.SetupWebhook},
+}
+
+// enabled returns the kinds selected by the supplied selector whose CRDs are
+// installed. The CRDs are looked up with the supplied manager, and kinds whose
+// CRD is missing are logged and skipped.
+func enabled(mgr ctrl.Manager, s Selector, log logging.Logger) ([]kind, error) {
+	out := make([]kind, 0, len(kinds))
+	for _, k := range kinds {
+		if !s.Selects(k.gvk.GroupKind()) {
+			log.Debug("Controller is not enabled", "kind", k.gvk.GroupKind().String())
+			continue
+		}
+		ok, err := installed(mgr.GetRESTMapper(), k.gvk)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			log.Info("Skipping controller because its CRD is not installed", "kind", k.gvk.GroupKind().String())
+			continue
+		}
+		out = append(out, k)
+	}
+	return out, nil
+}
+
+// Setup creates the ProviderConfig controller and the controllers of the
+// Dynatrace kinds selected by the supplied selector whose CRDs are installed,
+// and adds them to the supplied manager.
+func Setup(mgr ctrl.Manager, o controller.Options, s Selector) error {
+	if err := config.Setup(mgr, o); err != nil {
+		return err
+	}
+	ks, err := enabled(mgr, s, o.Logger)
+	if err != nil {
+		return err
+	}
+	for _, k := range ks {
+		if err := k.setup(mgr, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exporters returns the Exporters of all Dynatrace kinds. Profiles come first,
+// because other kinds reference them.
+func Exporters() []generic.Exporter {
+	return []generic.Exporter{
+		profile.Exporter(),
+		email.Exporter(),
+		slack.Exporter(),
+		autotag.Exporter(),
+	}
+}
+
+// Planners returns the Planners of all Dynatrace kinds. Kinds come before the
+// kinds whose references resolve to their observed state: AutoTags before
+// Profiles, whose tag references resolve to their names, and Profiles before
+// notifications, whose references resolve to their IDs.
+func Planners() []generic.Planner {
+	return []generic.Planner{
+		autotag.Planner(),
+		profile.Planner(),
+		email.Planner(),
+		slack.Planner(),
+	}
+}
+
+// SetupWebhooks adds the webhooks that validate the managed resources of the
+// Dynatrace kinds selected by the supplied selector whose CRDs are installed
+// to the supplied manager.
+func SetupWebhooks(mgr ctrl.Manager, s Selector) error {
+	ks, err := enabled(mgr, s, logging.NewNopLogger())
+	if err != nil {
+		return err
+	}
+	for _, k := range ks {
+		if err := k.setupWebhook(mgr); err != nil {
 			return err
 		}
 	}
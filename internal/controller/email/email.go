@@ -17,191 +17,100 @@ limitations under the License.
 package email
 
 import (
-	"context"
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/meta"
-	"github.com/crossplane/provider-dynatrace/internal/credentials"
 	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
 	notificationSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications/email/settings"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"net/http"
-
 	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
-	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
-	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
-	"github.com/crossplane/provider-dynatrace/internal/features"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+	"github.com/crossplane/provider-dynatrace/internal/controller/profile"
+	"github.com/crossplane/provider-dynatrace/internal/credentials"
+	"github.com/crossplane/provider-dynatrace/internal/drift"
+	"github.com/crossplane/provider-dynatrace/internal/lateinit"
 )
 
-const (
-	errNotEmail     = "managed resource is not a Email custom resource"
-	errTrackPCUsage = "cannot track ProviderConfig usage"
-	errGetPC        = "cannot get ProviderConfig"
-	errGetCreds     = "cannot get credentials"
-
-	errNewClient = "cannot create new Service"
-)
+// errNoAlertingProfile is returned when no alerting profile was specified or
+// resolved from a reference or selector.
+const errNoAlertingProfile = "spec.forProvider.alertingProfile is required; specify it, a reference or a selector"
+
+// kind maps Email managed resources to email notification settings objects.
+var kind = &generic.Kind[*v1alpha1.Email, notifications.Notification]{
+	GroupKind:        v1alpha1.EmailGroupKind,
+	GroupVersionKind: v1alpha1.EmailGroupVersionKind,
+	Name:             func(cr *v1alpha1.Email) string { return cr.Spec.ForProvider.Name },
+	ToDTO: func(cr *v1alpha1.Email) (notifications.Notification, error) {
+		return crdToDto(cr.Spec.ForProvider)
+	},
+	LateInitialize: func(cr *v1alpha1.Email, n notifications.Notification) bool {
+		return lateInitialize(&cr.Spec.ForProvider, n)
+	},
+	Observe: func(cr *v1alpha1.Email, id string, o *settings20.Object, n notifications.Notification) error {
+		cr.Status.AtProvider = generateObservation(id, o, n)
+		return nil
+	},
+	Validate: func(cr *v1alpha1.Email) field.ErrorList {
+		return validate(cr.Spec.ForProvider)
+	},
+	Spec: func(cr *v1alpha1.Email) *apisv1alpha1.SettingsObjectSpec {
+		return &cr.Spec.SettingsObjectSpec
+	},
+	Metadata: func(cr *v1alpha1.Email) *apisv1alpha1.SettingsObjectObservation {
+		return &cr.Status.AtProvider.SettingsObjectObservation
+	},
+	FromDTO: func(n notifications.Notification) (*v1alpha1.Email, []string, error) {
+		return &v1alpha1.Email{Spec: v1alpha1.EmailSpec{ForProvider: dtoToCrd(n)}}, nil, nil
+	},
+	Reference: func(cr *v1alpha1.Email, name generic.NameFunc) {
+		cr.Spec.ForProvider.AlertingProfile, cr.Spec.ForProvider.AlertingProfileRef = profile.Ref(cr.Spec.ForProvider.AlertingProfile, name)
+	},
+	// Recipients, CC and BCC recipients are sets in the notification schema.
+	DiffOptions: []cmp.Option{cmpopts.IgnoreFields(notifications.Notification{}, "LegacyID"), drift.Unordered[string]()},
+}
 
-func newService(data []byte) (settings.CRUDService[*notifications.Notification], error) {
+func newService(data []byte) (settings20.CRUDService[*notifications.Notification], error) {
 	c, err := credentials.Unmarshal(data)
 	if err != nil {
 		return nil, err
 	}
 
-	return notifications.Service(c, notifications.Types.Email), nil
+	// Notifications of all types share a schema.
+	ofEmail := func(n *notifications.Notification) bool { return n.Type == notifications.Types.Email }
+	return settings20.NewService(notifications.SchemaID, settings20.NewClient(c), settings20.WithFilter(ofEmail)), nil
 }
 
 // Setup adds a controller that reconciles Email managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
-	name := managed.ControllerName(v1alpha1.EmailGroupKind)
-
-	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
-	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
-		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
-	}
-
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.EmailGroupVersionKind),
-		managed.WithExternalConnecter(&connector{
-			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newService}),
-		managed.WithLogger(o.Logger.WithValues("controller", name)),
-		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithConnectionPublishers(cps...))
-
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.Email{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
-}
-
-// A connector is expected to produce an ExternalClient when its Connect method
-// is called.
-type connector struct {
-	kube         client.Client
-	usage        resource.Tracker
-	newServiceFn func(creds []byte) (settings.CRUDService[*notifications.Notification], error)
+	return generic.Setup(mgr, o, kind, newService)
 }
 
-// Connect typically produces an ExternalClient by:
-// 1. Tracking that the managed resource is using a ProviderConfig.
-// 2. Getting the managed resource's ProviderConfig.
-// 3. Getting the credentials specified by the ProviderConfig.
-// 4. Using the credentials to form a client.
-func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*v1alpha1.Email)
-	if !ok {
-		return nil, errors.New(errNotEmail)
-	}
-
-	if err := c.usage.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackPCUsage)
-	}
-
-	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetPC)
-	}
-
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
-	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
-	}
-
-	svc, err := c.newServiceFn(data)
-	if err != nil {
-		return nil, errors.Wrap(err, errNewClient)
-	}
-
-	return &external{service: svc}, nil
+// Exporter returns an Exporter of email notifications.
+func Exporter() generic.Exporter {
+	return generic.NewExporter(kind, newService)
 }
 
-// An ExternalClient observes, then either creates, updates, or deletes an
-// external resource to ensure it reflects the managed resource's desired state.
-type external struct {
-	// A 'client' used to connect to the external resource API. In practice this
-	// would be something like an AWS SDK client.
-	service settings.CRUDService[*notifications.Notification]
+// Planner returns a Planner of email notifications.
+func Planner() generic.Planner {
+	return generic.NewPlanner(kind, newService)
 }
 
-func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.Email)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotEmail)
-	}
-
-	id := meta.GetExternalName(cr)
-	var n notifications.Notification
-	err := c.service.Get(id, &n)
-	if err != nil {
-		var restError rest.Error
-		if errors.As(err, &restError) {
-			if restError.Code == http.StatusNotFound {
-				return managed.ExternalObservation{ResourceExists: false}, nil
-			}
-		}
-
-		return managed.ExternalObservation{}, err
-	}
-
-	cr.Status.SetConditions(xpv1.Available())
-	cr.Status.AtProvider.ID = id
-
-	local := crdToDto(cr.Spec.ForProvider)
-	if diff := cmp.Diff(n, local, cmpopts.IgnoreFields(notifications.Notification{}, "LegacyID"), cmpopts.EquateEmpty()); diff != "" {
-
-		return managed.ExternalObservation{
-			ResourceExists:   true,
-			ResourceUpToDate: false,
-			Diff:             diff,
-		}, nil
-	}
-
-	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
-	}, nil
+// SetupWebhook adds a webhook that validates Email managed resources.
+func SetupWebhook(mgr ctrl.Manager) error {
+	return generic.SetupWebhook(mgr, kind)
 }
 
-func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.Email)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotEmail)
-	}
-
-	cr.Status.SetConditions(xpv1.Creating())
-
-	n := crdToDto(cr.Spec.ForProvider)
-	apiResp, err := c.service.Create(&n)
-	if err != nil {
-		return managed.ExternalCreation{}, err
+func crdToDto(v v1alpha1.EmailParameters) (notifications.Notification, error) {
+	if v.AlertingProfile == nil {
+		return notifications.Notification{}, errors.New(errNoAlertingProfile)
 	}
 
-	meta.SetExternalName(cr, apiResp.ID)
-
-	return managed.ExternalCreation{}, nil
-}
-
-func crdToDto(v v1alpha1.EmailParameters) notifications.Notification {
-
 	n := notifications.Notification{
 		Type:      notifications.Types.Email,
 		Enabled:   v.Enabled,
@@ -217,36 +126,54 @@ func crdToDto(v v1alpha1.EmailParameters) notifications.Notification {
 			Body:                 v.Body,
 		},
 	}
-	return n
+	return n, nil
 }
 
-func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*v1alpha1.Email)
-	if !ok {
-		return managed.ExternalUpdate{}, errors.New(errNotEmail)
+func dtoToCrd(n notifications.Notification) v1alpha1.EmailParameters {
+	p := v1alpha1.EmailParameters{
+		Enabled:         n.Enabled,
+		Name:            n.Name,
+		AlertingProfile: &n.ProfileID,
 	}
 
-	id := meta.GetExternalName(cr)
-	n := crdToDto(cr.Spec.ForProvider)
-	err := c.service.Update(id, &n)
-	if err != nil {
-		return managed.ExternalUpdate{}, err
+	if e := n.Email; e != nil {
+		p.Subject = e.Subject
+		p.NotifyClosedProblems = e.NotifyClosedProblems
+		p.Body = e.Body
+		p.To = e.Recipients
+		p.Cc = e.CCRecipients
+		p.Bcc = e.BCCRecipients
 	}
 
-	return managed.ExternalUpdate{}, nil
+	return p
 }
 
-func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.Email)
-	if !ok {
-		return errors.New(errNotEmail)
+// lateInitialize fills unset optional parameters from the observed
+// notification.
+func lateInitialize(in *v1alpha1.EmailParameters, n notifications.Notification) bool {
+	li := &lateinit.Tracker{}
+	lateinit.String(li, &in.AlertingProfile, n.ProfileID)
+	return li.Changed()
+}
+
+// generateObservation returns the observed state of the supplied notification.
+func generateObservation(id string, o *settings20.Object, n notifications.Notification) v1alpha1.EmailObservation {
+	obs := v1alpha1.EmailObservation{
+		ID:                        id,
+		SettingsObjectObservation: o.Observation(),
+		Enabled:                   n.Enabled,
+		Name:                      n.Name,
+		AlertingProfile:           n.ProfileID,
 	}
 
-	err := c.service.Delete(meta.GetExternalName(cr))
-	if err != nil {
-		return err
+	if e := n.Email; e != nil {
+		obs.Subject = e.Subject
+		obs.NotifyClosedProblems = e.NotifyClosedProblems
+		obs.Body = e.Body
+		obs.To = e.Recipients
+		obs.Cc = e.CCRecipients
+		obs.Bcc = e.BCCRecipients
 	}
 
-	cr.Status.SetConditions(xpv1.Deleting())
-	return nil
+	return obs
 }
@@ -18,15 +18,27 @@ package email
 
 import (
 	"context"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
+	"net/http"
 	"testing"
 
-	"github.com/google/go-cmp/cmp"
-
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
+	notificationSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications/email/settings"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20/fake"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic/generictest"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -37,14 +49,55 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
+func emailParams() v1alpha1.EmailParameters {
+	profile := "profile-id"
+	return v1alpha1.EmailParameters{
+		Enabled:         true,
+		Name:            "cool-email",
+		Subject:         "Problem",
+		Body:            "{ProblemDetailsHTML}",
+		To:              []string{"ops@example.org"},
+		AlertingProfile: &profile,
+	}
+}
+
+func email(id string) *v1alpha1.Email {
+	cr := &v1alpha1.Email{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		Spec:       v1alpha1.EmailSpec{ForProvider: emailParams()},
+	}
+	if id != "" {
+		meta.SetExternalName(cr, id)
+	}
+	return cr
+}
+
+func desired(t *testing.T) notifications.Notification {
+	t.Helper()
+	n, err := crdToDto(emailParams())
+	if err != nil {
+		t.Fatalf("crdToDto(...): %v", err)
+	}
+	return n
+}
+
 func TestObserve(t *testing.T) {
-	type fields struct {
-		service settings.CRUDService[*notifications.Notification]
+	api := fake.NewServer()
+	defer api.Close()
+
+	svc, err := newService(api.Credentials())
+	if err != nil {
+		t.Fatalf("newService(...): %v", err)
 	}
 
+	upToDate := api.Create(notifications.SchemaID, fake.ScopeEnvironment, desired(t))
+	changed := desired(t)
+	changed.Email.Subject = "Changed"
+	drifted := api.Create(notifications.SchemaID, fake.ScopeEnvironment, changed)
+
 	type args struct {
-		ctx context.Context
-		mg  resource.Managed
+		fault *fake.Fault
+		mg    resource.Managed
 	}
 
 	type want struct {
@@ -54,23 +107,247 @@ func TestObserve(t *testing.T) {
 
 	cases := map[string]struct {
 		reason string
-		fields fields
 		args   args
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NotFound": {
+			reason: "A notification that does not exist should be reported as such.",
+			args:   args{mg: email("missing")},
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"UpToDate": {
+			reason: "A notification that matches the desired state should be up to date.",
+			args:   args{mg: email(upToDate)},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}},
+		},
+		"Drifted": {
+			reason: "A notification that differs from the desired state should not be up to date.",
+			args:   args{mg: email(drifted)},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}},
+		},
+		"Forbidden": {
+			reason: "Errors reading the notification should be returned.",
+			args: args{
+				fault: &fake.Fault{Method: http.MethodGet, Code: http.StatusForbidden, Message: "Token is missing required scope."},
+				mg:    email(upToDate),
+			},
+			want: want{err: rest.Error{Code: http.StatusForbidden, Message: "Token is missing required scope."}},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{service: tc.fields.service}
-			got, err := e.Observe(tc.args.ctx, tc.args.mg)
+			if tc.args.fault != nil {
+				api.Inject(*tc.args.fault)
+			}
+			e := generic.NewExternal(kind, svc)
+			got, err := e.Observe(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+			if diff := cmp.Diff(tc.want.o, got, cmpopts.IgnoreFields(managed.ExternalObservation{}, "Diff")); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
 		})
 	}
 }
+
+func TestReconcile(t *testing.T) {
+	env := generictest.New(t, kind, newService)
+	cr := email("")
+	cr.SetGeneration(1)
+	env.Create(t, cr)
+
+	// A notification the API rejects as invalid is not created.
+	env.API.Inject(fake.Fault{
+		Method:               http.MethodPost,
+		ValidateOnly:         true,
+		Code:                 http.StatusBadRequest,
+		Message:              "Validation failed",
+		ConstraintViolations: []rest.ConstraintViolation{{Path: "emailNotification.recipients", Message: "not a valid email address"}},
+	})
+	env.Reconcile(t, cr)
+	if got := env.API.Objects(notifications.SchemaID); len(got) != 0 {
+		t.Fatalf("Reconcile(...): rejected notification was created: %v", got)
+	}
+	generictest.CheckCondition(t, cr, apisv1alpha1.Invalid(""))
+	if got := cr.Status.AtProvider.RejectedGeneration; got != 1 {
+		t.Errorf("Reconcile(...): status.atProvider.rejectedGeneration: want 1, got %d", got)
+	}
+
+	// The rejected desired state is not sent again.
+	env.Reconcile(t, cr)
+	if got := env.API.Objects(notifications.SchemaID); len(got) != 0 {
+		t.Fatalf("Reconcile(...): rejected notification was created: %v", got)
+	}
+	generictest.CheckCondition(t, cr, xpv1.ReconcileError(errors.New("rejected")))
+
+	// A new desired state is.
+	cr.SetGeneration(2)
+	env.Update(t, cr)
+	env.Reconcile(t, cr)
+	id := meta.GetExternalName(cr)
+	got := notifications.Notification{}
+	env.Value(t, id, &got)
+	if diff := cmp.Diff(desired(t), got); diff != "" {
+		t.Errorf("Reconcile(...): -want notification, +got:\n%s\n", diff)
+	}
+
+	env.Reconcile(t, cr)
+	generictest.CheckCondition(t, cr, xpv1.Available())
+	generictest.CheckCondition(t, cr, apisv1alpha1.Valid())
+
+	env.Delete(t, cr)
+	env.Reconcile(t, cr)
+	if _, ok := env.API.Object(id); ok {
+		t.Fatalf("Reconcile(...): notification was not deleted")
+	}
+	if env.Reconcile(t, cr) {
+		t.Errorf("Reconcile(...): managed resource of deleted notification still exists")
+	}
+}
+
+func TestCrdToDto(t *testing.T) {
+	profile := "profile-id"
+
+	type want struct {
+		n   notifications.Notification
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.EmailParameters
+		want   want
+	}{
+		"WithAlertingProfile": {
+			reason: "A notification with an alerting profile should be converted.",
+			in:     v1alpha1.EmailParameters{Enabled: true, Name: "cool", Subject: "Problem", Body: "{ProblemDetailsHTML}", To: []string{"ops@example.org"}, AlertingProfile: &profile},
+			want: want{n: notifications.Notification{
+				Type:      notifications.Types.Email,
+				Enabled:   true,
+				Name:      "cool",
+				ProfileID: profile,
+				Email:     &notificationSettings.Email{Subject: "Problem", Body: "{ProblemDetailsHTML}", Recipients: []string{"ops@example.org"}},
+			}},
+		},
+		"NilAlertingProfile": {
+			reason: "A notification whose alerting profile was neither specified nor resolved should return an error rather than panic.",
+			in:     v1alpha1.EmailParameters{Enabled: true, Name: "cool", Subject: "Problem", Body: "{ProblemDetailsHTML}", To: []string{"ops@example.org"}},
+			want:   want{err: errors.New(errNoAlertingProfile)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			n, err := crdToDto(tc.in)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncrdToDto(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.n, n); diff != "" {
+				t.Errorf("\n%s\ncrdToDto(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	profile := "profile-id"
+
+	params := v1alpha1.EmailParameters{
+		Enabled:         true,
+		Name:            "cool-email",
+		Subject:         "Problem",
+		Body:            "{ProblemDetailsHTML}",
+		To:              []string{"a@example.com", "b@example.com"},
+		Cc:              []string{"c@example.com", "d@example.com"},
+		Bcc:             []string{"e@example.com", "f@example.com"},
+		AlertingProfile: &profile,
+	}
+
+	observed := func(to, cc, bcc []string) notifications.Notification {
+		return notifications.Notification{
+			Type:      notifications.Types.Email,
+			Enabled:   true,
+			Name:      "cool-email",
+			ProfileID: profile,
+			Email: &notificationSettings.Email{
+				Subject:       "Problem",
+				Body:          "{ProblemDetailsHTML}",
+				Recipients:    to,
+				CCRecipients:  cc,
+				BCCRecipients: bcc,
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		reason   string
+		observed notifications.Notification
+		want     []string
+	}{
+		"SameOrder": {
+			reason:   "A notification observed as declared should not drift.",
+			observed: observed(params.To, params.Cc, params.Bcc),
+		},
+		"Reordered": {
+			reason:   "Recipients are sets, so their order should not drift.",
+			observed: observed([]string{"b@example.com", "a@example.com"}, []string{"d@example.com", "c@example.com"}, []string{"f@example.com", "e@example.com"}),
+		},
+		"Changed": {
+			reason:   "A changed recipient should drift.",
+			observed: observed([]string{"x@example.com", "b@example.com"}, params.Cc, params.Bcc),
+			want:     []string{"emailNotification.recipients"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.Email{Spec: v1alpha1.EmailSpec{ForProvider: *params.DeepCopy()}}
+			kind.LateInitialize(cr, tc.observed)
+			desired, err := kind.ToDTO(cr)
+			if err != nil {
+				t.Fatalf("kind.ToDTO(...): %v", err)
+			}
+			declared, err := crdToDto(params)
+			if err != nil {
+				t.Fatalf("crdToDto(...): %v", err)
+			}
+			if diff := cmp.Diff(desired, declared); diff != "" {
+				t.Errorf("\n%s\nkind.ToDTO(...): -after late-init, +declared:\n%s\n", tc.reason, diff)
+			}
+			got := kind.Compare(cr, tc.observed, desired)
+			if diff := cmp.Diff(tc.want, got.Paths); diff != "" {
+				t.Errorf("\n%s\nkind.Compare(...): -want drifted paths, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDtoToCrd(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.EmailParameters
+	}{
+		"AllParameters": {
+			reason: "An exported notification should desire the notification it was exported from.",
+			in:     v1alpha1.EmailParameters{Enabled: true, NotifyClosedProblems: true, Name: "cool", Subject: "Problem", Body: "{ProblemDetailsHTML}", To: []string{"ops@example.org"}, Cc: []string{"cc@example.org"}, Bcc: []string{"bcc@example.org"}, AlertingProfile: emailParams().AlertingProfile},
+		},
+		"RequiredParameters": {
+			reason: "Unset optional parameters should remain unset.",
+			in:     emailParams(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			n, err := crdToDto(tc.in)
+			if err != nil {
+				t.Fatalf("crdToDto(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.in, dtoToCrd(n)); diff != "" {
+				t.Errorf("\n%s\ndtoToCrd(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
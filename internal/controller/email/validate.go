@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package email
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
+)
+
+const maxRecipients = 100
+
+// validate returns the constraints of the email notification schema the
+// supplied parameters violate.
+func validate(in v1alpha1.EmailParameters) field.ErrorList {
+	p := field.NewPath("spec", "forProvider")
+	errs := field.ErrorList{}
+
+	if strings.TrimSpace(in.Name) == "" {
+		errs = append(errs, field.Required(p.Child("name"), "the name of the notification must not be blank"))
+	}
+	if in.Subject == "" {
+		errs = append(errs, field.Required(p.Child("subject"), "the subject of the email must not be empty"))
+	}
+	if in.Body == "" {
+		errs = append(errs, field.Required(p.Child("body"), "the content of the email must not be empty"))
+	}
+
+	if len(in.To) == 0 {
+		errs = append(errs, field.Required(p.Child("to"), "at least one recipient must be specified"))
+	}
+	if len(in.To) > maxRecipients {
+		errs = append(errs, field.TooMany(p.Child("to"), len(in.To), maxRecipients))
+	}
+	if len(in.Cc) > maxRecipients {
+		errs = append(errs, field.TooMany(p.Child("cc"), len(in.Cc), maxRecipients))
+	}
+	if len(in.Bcc) > maxRecipients {
+		errs = append(errs, field.TooMany(p.Child("bcc"), len(in.Bcc), maxRecipients))
+	}
+
+	if in.AlertingProfile == nil && in.AlertingProfileRef == nil && in.AlertingProfileSelector == nil {
+		errs = append(errs, field.Required(p.Child("alertingProfile"), "an alerting profile, a reference or a selector must be specified"))
+	}
+
+	return errs
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package email
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	p := field.NewPath("spec", "forProvider")
+
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.EmailParameters
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "A notification referencing its alerting profile should be valid.",
+			in: v1alpha1.EmailParameters{
+				Name:               "cool-email",
+				Subject:            "Problem",
+				Body:               "{ProblemDetailsHTML}",
+				To:                 []string{"ops@example.org"},
+				AlertingProfileRef: &xpv1.Reference{Name: "cool-profile"},
+			},
+			want: field.ErrorList{},
+		},
+		"Missing": {
+			reason: "Recipients and an alerting profile should be required.",
+			in: v1alpha1.EmailParameters{
+				Name:    "cool-email",
+				Subject: "Problem",
+				Body:    "{ProblemDetailsHTML}",
+				Cc:      make([]string, 101),
+			},
+			want: field.ErrorList{
+				field.Required(p.Child("to"), "at least one recipient must be specified"),
+				field.TooMany(p.Child("cc"), 101, 100),
+				field.Required(p.Child("alertingProfile"), "an alerting profile, a reference or a selector must be specified"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := validate(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+)
+
+const (
+	errList             = "cannot list settings objects"
+	errFmtNotExportable = "%s cannot be exported"
+	errFmtFromDTO       = "cannot convert settings object %s to managed resource"
+	errFmtNotSettings   = "settings object %s is a %T"
+)
+
+// An Exported managed resource desires a settings object that exists in a
+// tenant.
+type Exported struct {
+	// ID of the settings object.
+	ID string
+
+	// Name of the settings object.
+	Name string
+
+	// Managed resource that desires the settings object. Only its spec is
+	// set.
+	Managed resource.Managed
+
+	// Incomplete lists the paths of parameters that could not be exported,
+	// e.g. secrets the API does not return.
+	Incomplete []string
+}
+
+// A NameFunc returns the name of the exported managed resource of the supplied
+// GroupKind that desires the settings object with the supplied ID.
+type NameFunc func(groupKind, id string) (string, bool)
+
+// An Exporter exports the settings objects of a kind as managed resources.
+type Exporter interface {
+	// GroupKind of the exported managed resources.
+	GroupKind() string
+
+	// Export lists the settings objects of the kind using the supplied
+	// credentials.
+	Export(creds []byte) ([]Exported, error)
+
+	// Reference replaces the IDs of settings objects of other kinds in the
+	// spec of the supplied exported managed resource with references to the
+	// exported managed resources that desire them.
+	Reference(mg resource.Managed, name NameFunc)
+}
+
+// NewExporter returns an Exporter for the supplied kind, using newService to
+// create the settings service for a set of credentials.
+func NewExporter[M resource.Managed, D any, PD Settings[D]](k *Kind[M, D], newService func(creds []byte) (settings20.CRUDService[PD], error)) Exporter {
+	return &exporter[M, D, PD]{kind: k, newServiceFn: newService}
+}
+
+type exporter[M resource.Managed, D any, PD Settings[D]] struct {
+	kind         *Kind[M, D]
+	newServiceFn func(creds []byte) (settings20.CRUDService[PD], error)
+}
+
+func (e *exporter[M, D, PD]) GroupKind() string {
+	return e.kind.GroupKind
+}
+
+func (e *exporter[M, D, PD]) Export(creds []byte) ([]Exported, error) {
+	if e.kind.FromDTO == nil {
+		return nil, errors.Errorf(errFmtNotExportable, e.kind.GroupKind)
+	}
+
+	svc, err := e.newServiceFn(creds)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	stubs, err := svc.List()
+	if err != nil {
+		return nil, errors.Wrap(err, errList)
+	}
+
+	out := make([]Exported, 0, len(stubs))
+	for _, s := range stubs {
+		v, ok := s.Value.(PD)
+		if !ok || v == nil {
+			return nil, errors.Errorf(errFmtNotSettings, s.ID, s.Value)
+		}
+		cr, incomplete, err := e.kind.FromDTO(*v)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtFromDTO, s.ID)
+		}
+		cr.GetObjectKind().SetGroupVersionKind(e.kind.GroupVersionKind)
+		out = append(out, Exported{ID: s.ID, Name: e.kind.Name(cr), Managed: cr, Incomplete: incomplete})
+	}
+	return out, nil
+}
+
+func (e *exporter[M, D, PD]) Reference(mg resource.Managed, name NameFunc) {
+	cr, ok := mg.(M)
+	if !ok || e.kind.Reference == nil {
+		return
+	}
+	e.kind.Reference(cr, name)
+}
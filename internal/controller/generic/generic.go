@@ -0,0 +1,605 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package generic implements a managed resource reconciler for kinds that
+// map one-to-one to Dynatrace Settings 2.0 objects. A kind only needs to
+// declare how its managed resource converts to the settings object and how
+// the observed object is reported; connecting, adopting, late-initializing,
+// diffing and status conditions are handled here.
+package generic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/adopt"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/drift"
+	"github.com/crossplane/provider-dynatrace/internal/metrics"
+	"github.com/crossplane/provider-dynatrace/internal/tracing"
+)
+
+const (
+	errFmtNotKind   = "managed resource is not a %s custom resource"
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+	errGetPC        = "cannot get ProviderConfig"
+	errGetCreds     = "cannot get credentials"
+	errNewClient    = "cannot create new Service"
+
+	errToDTO    = "cannot convert managed resource to settings object"
+	errValidate = "cannot validate settings object"
+	errCreate   = "cannot create settings object"
+	errUpdate   = "cannot update settings object"
+	errDelete   = "cannot delete settings object"
+
+	errListDependents = "cannot list settings objects referencing the settings object"
+
+	errPersistRejection = "cannot persist rejection of the desired state"
+
+	errFmtUpdateConflict = "refusing to update: the settings object was modified by %q since it was last observed; it will be compared again on the next reconcile"
+	errFmtDependents     = "refusing to delete: the settings object is referenced by %s; annotate the managed resource with %s: \"true\" to delete it anyway"
+	errFmtRejected       = "refusing to create or update: the API rejected generation %d of the desired state as invalid; it will not be sent again until the spec changes"
+)
+
+const (
+	reasonDriftDetected event.Reason = "DriftDetected"
+
+	msgFmtDriftDetected = "Settings object drifted from the desired state in: %s"
+)
+
+// A Settings is a pointer to a settings object of type D.
+type Settings[D any] interface {
+	*D
+	settings.Settings
+}
+
+// A Kind describes how managed resources of type M map to settings objects of
+// type D.
+type Kind[M resource.Managed, D any] struct {
+	// GroupKind and GroupVersionKind of the managed resource.
+	GroupKind        string
+	GroupVersionKind schema.GroupVersionKind
+
+	// Name returns the name of the settings object desired by the supplied
+	// managed resource. It is used to import existing objects by name.
+	Name func(cr M) string
+
+	// ToDTO converts the desired state of the supplied managed resource into
+	// a settings object. Errors other than those returned by Unresolved mark
+	// the desired state invalid.
+	ToDTO func(cr M) (D, error)
+
+	// LateInitialize fills unset optional parameters of the supplied managed
+	// resource from the observed settings object and returns true if any
+	// parameter was changed. It is optional.
+	LateInitialize func(cr M, observed D) bool
+
+	// Observe records the observed settings object in the status of the
+	// supplied managed resource.
+	Observe func(cr M, id string, o *settings20.Object, observed D) error
+
+	// Spec returns the settings object configuration in the spec of the
+	// supplied managed resource.
+	Spec func(cr M) *apisv1alpha1.SettingsObjectSpec
+
+	// Validate returns the invariants of the settings object the supplied
+	// managed resource violates that the API would reject, e.g. parameters
+	// required depending on the value of others. It is optional and checked
+	// by the validating webhook.
+	Validate func(cr M) field.ErrorList
+
+	// Warnings returns warnings about the supplied managed resource that do
+	// not make it invalid, e.g. about deprecated parameters it sets. It is
+	// optional and returned by the validating webhook.
+	Warnings func(cr M) []string
+
+	// Metadata returns the observed settings object metadata recorded in the
+	// status of the supplied managed resource.
+	Metadata func(cr M) *apisv1alpha1.SettingsObjectObservation
+
+	// DiffOptions are used in addition to the drift package defaults to
+	// compare the observed and the desired settings object, e.g. to ignore
+	// the order of lists Dynatrace treats as sets.
+	DiffOptions []cmp.Option
+
+	// Drift returns the paths of properties that drifted in ways a field by
+	// field comparison cannot detect, e.g. write-only secrets. It is
+	// optional.
+	Drift func(cr M, observed D) []string
+
+	// Dependents returns descriptions of the settings objects in the tenant
+	// that reference the settings object with the supplied ID and would
+	// break if it was deleted, using the supplied credentials. Deletion is
+	// refused while there are any, unless forced by annotation. It is
+	// optional.
+	Dependents func(creds []byte, id string) ([]string, error)
+
+	// FromDTO converts an observed settings object into a managed resource
+	// that desires it and returns the paths of parameters it cannot set,
+	// e.g. secrets the API does not return or list elements with values the
+	// managed resource does not support. It is used to export the
+	// settings objects of a tenant; kinds without it cannot be exported.
+	FromDTO func(observed D) (M, []string, error)
+
+	// Reference replaces the IDs of settings objects of other kinds in the
+	// spec of the supplied exported managed resource with references to
+	// the exported managed resources that desire them. It is optional.
+	Reference func(cr M, name NameFunc)
+}
+
+// Compare the observed and the desired settings object of the supplied
+// managed resource, ignoring the paths listed in its ignore-drift annotation.
+func (k *Kind[M, D]) Compare(cr M, observed, desired D) drift.Result {
+	opts := append([]cmp.Option{drift.IgnorePaths(drift.IgnoredPaths(cr)...)}, k.DiffOptions...)
+	d := drift.Compare(observed, desired, opts...)
+	if k.Drift != nil {
+		d = d.With(k.Drift(cr, observed)...)
+	}
+	return d
+}
+
+// A Connector produces an External client for a kind by reading the
+// credentials of the managed resource's ProviderConfig.
+type Connector[M resource.Managed, D any, PD Settings[D]] struct {
+	kube         client.Client
+	usage        resource.Tracker
+	recorder     event.Recorder
+	newServiceFn func(creds []byte) (settings20.CRUDService[PD], error)
+	kind         *Kind[M, D]
+}
+
+// NewConnector returns a Connector for the supplied kind. Its External
+// clients emit events using the supplied recorder.
+func NewConnector[M resource.Managed, D any, PD Settings[D]](kube client.Client, usage resource.Tracker, recorder event.Recorder, k *Kind[M, D], newService func(creds []byte) (settings20.CRUDService[PD], error)) *Connector[M, D, PD] {
+	return &Connector[M, D, PD]{kube: kube, usage: usage, recorder: recorder, newServiceFn: newService, kind: k}
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *Connector[M, D, PD]) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ctx, span := tracing.Start(ctx, "Connect", c.kind.GroupKind, mg)
+	ext, err := c.connect(ctx, mg)
+	tracing.End(span, mg, err)
+	return ext, err
+}
+
+func (c *Connector[M, D, PD]) connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(M)
+	if !ok {
+		return nil, errors.Errorf(errFmtNotKind, c.kind.GroupKind)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	svc, err := c.newServiceFn(data)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	ext := NewExternal[M, D, PD](c.kind, svc)
+	ext.kube = c.kube
+	ext.recorder = c.recorder
+	if c.kind.Dependents != nil {
+		ext.dependents = func(id string) ([]string, error) { return c.kind.Dependents(data, id) }
+	}
+	return ext, nil
+}
+
+// An External observes, then either creates, updates, or deletes a settings
+// object to ensure it reflects the managed resource's desired state.
+type External[M resource.Managed, D any, PD Settings[D]] struct {
+	kube     client.Client
+	kind     *Kind[M, D]
+	service  settings20.CRUDService[PD]
+	recorder event.Recorder
+
+	// dependents lists the settings objects referencing the settings object
+	// with the supplied ID. It is nil for kinds without dependents.
+	dependents func(id string) ([]string, error)
+}
+
+// NewExternal returns an External client for the supplied kind that uses the
+// supplied service.
+func NewExternal[M resource.Managed, D any, PD Settings[D]](k *Kind[M, D], svc settings20.CRUDService[PD]) *External[M, D, PD] {
+	return &External[M, D, PD]{kind: k, service: svc, recorder: event.NewNopRecorder()}
+}
+
+// Observe the settings object of the supplied managed resource.
+func (e *External[M, D, PD]) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, span := tracing.Start(ctx, "Observe", e.kind.GroupKind, mg)
+	o, err := e.observe(ctx, mg)
+	tracing.End(span, mg, err)
+	return o, err
+}
+
+func (e *External[M, D, PD]) observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(M)
+	if !ok {
+		return managed.ExternalObservation{}, errors.Errorf(errFmtNotKind, e.kind.GroupKind)
+	}
+
+	adopted, err := adopt.ByName(cr, e.traced(ctx), e.kind.Name(cr))
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	id := meta.GetExternalName(cr)
+
+	if e.kind.Spec(cr).ValidateOnly {
+		return e.validateOnly(ctx, cr, id)
+	}
+
+	var observed D
+	o, err := e.traced(ctx).GetObject(id, PD(&observed))
+	if settings20.IsNotFound(err) {
+		// A rejected desired state must not block deletion, which the
+		// managed reconciler only reaches if Observe succeeds.
+		if !meta.WasDeleted(cr) && rejected(e.kind.Metadata(cr), cr) {
+			return managed.ExternalObservation{}, errors.Errorf(errFmtRejected, cr.GetGeneration())
+		}
+		cr.SetConditions(apisv1alpha1.APIRequestSucceeded())
+		// Report a desired state that cannot be converted before Create,
+		// which discards status changes if it fails.
+		if !meta.WasDeleted(cr) {
+			if _, err := e.toDTO(cr); err != nil {
+				return managed.ExternalObservation{}, err
+			}
+		}
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		e.classify(cr, err)
+		return managed.ExternalObservation{}, err
+	}
+
+	previous := *e.kind.Metadata(cr)
+
+	cr.SetConditions(xpv1.Available())
+	if err := e.kind.Observe(cr, id, o, observed); err != nil {
+		// An observed state that cannot be recorded must not block
+		// deletion either.
+		if meta.WasDeleted(cr) {
+			return managed.ExternalObservation{ResourceExists: true}, nil
+		}
+		return managed.ExternalObservation{}, err
+	}
+
+	li := false
+	if e.kind.LateInitialize != nil {
+		li = e.kind.LateInitialize(cr, observed)
+	}
+
+	desired, err := e.toDTO(cr)
+	if err != nil {
+		// A desired state that cannot be converted must not block deletion.
+		if meta.WasDeleted(cr) {
+			return managed.ExternalObservation{ResourceExists: true}, nil
+		}
+		return managed.ExternalObservation{}, err
+	}
+
+	d := e.kind.Compare(cr, observed, desired)
+	e.kind.Metadata(cr).Drift = d.Paths
+	if d.Drifted() && !cmp.Equal(previous.Drift, d.Paths) {
+		e.recorder.Event(cr, event.Normal(reasonDriftDetected, fmt.Sprintf(msgFmtDriftDetected, strings.Join(d.Paths, ", "))))
+		metrics.DriftDetected(e.kind.GroupKind)
+	}
+
+	// A rejection only matters while the object still has to be updated,
+	// not while it is being deleted.
+	if d.Drifted() && !meta.WasDeleted(cr) && rejected(&previous, cr) {
+		e.kind.Metadata(cr).RejectedGeneration = previous.RejectedGeneration
+		return managed.ExternalObservation{}, errors.Errorf(errFmtRejected, cr.GetGeneration())
+	}
+	cr.SetConditions(apisv1alpha1.APIRequestSucceeded())
+
+	// The API accepted the desired state if it stores it. Create reports
+	// validity too, but its status changes are lost when the external name
+	// is persisted.
+	if !d.Drifted() {
+		cr.SetConditions(apisv1alpha1.Valid())
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        !d.Drifted(),
+		ResourceLateInitialized: adopted || li,
+		Diff:                    d.Diff,
+	}, nil
+}
+
+// Create the settings object of the supplied managed resource.
+func (e *External[M, D, PD]) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, span := tracing.Start(ctx, "Create", e.kind.GroupKind, mg)
+	c, err := e.create(ctx, mg)
+	tracing.End(span, mg, err)
+	return c, err
+}
+
+func (e *External[M, D, PD]) create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(M)
+	if !ok {
+		return managed.ExternalCreation{}, errors.Errorf(errFmtNotKind, e.kind.GroupKind)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	desired, err := e.toDTO(cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	if err := e.traced(ctx).Validate("", PD(&desired)); err != nil {
+		return managed.ExternalCreation{}, e.failed(ctx, cr, errors.Wrap(err, errValidate))
+	}
+	cr.SetConditions(apisv1alpha1.Valid())
+
+	stub, err := e.traced(ctx).Create(PD(&desired))
+	if err != nil {
+		return managed.ExternalCreation{}, e.failed(ctx, cr, errors.Wrap(err, errCreate))
+	}
+
+	cr.SetConditions(apisv1alpha1.APIRequestSucceeded())
+	meta.SetExternalName(cr, stub.ID)
+
+	return managed.ExternalCreation{}, nil
+}
+
+// Update the settings object of the supplied managed resource, unless it was
+// modified since it was last observed.
+func (e *External[M, D, PD]) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, span := tracing.Start(ctx, "Update", e.kind.GroupKind, mg)
+	u, err := e.update(ctx, mg)
+	tracing.End(span, mg, err)
+	return u, err
+}
+
+func (e *External[M, D, PD]) update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(M)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.Errorf(errFmtNotKind, e.kind.GroupKind)
+	}
+
+	desired, err := e.toDTO(cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	id := meta.GetExternalName(cr)
+	if err := e.traced(ctx).Validate(id, PD(&desired)); err != nil {
+		return managed.ExternalUpdate{}, e.failed(ctx, cr, errors.Wrap(err, errValidate))
+	}
+	cr.SetConditions(apisv1alpha1.Valid())
+
+	err = e.traced(ctx).UpdateObject(id, PD(&desired), e.kind.Metadata(cr).UpdateToken)
+	if settings20.IsConflict(err) {
+		return managed.ExternalUpdate{}, e.conflict(ctx, cr)
+	}
+	if err != nil {
+		return managed.ExternalUpdate{}, e.failed(ctx, cr, errors.Wrap(err, errUpdate))
+	}
+
+	cr.SetConditions(apisv1alpha1.APIRequestSucceeded())
+	return managed.ExternalUpdate{}, nil
+}
+
+// conflict re-observes a settings object that was modified concurrently so
+// that its status reflects the version that blocked the update, and returns
+// an error explaining why the update was refused.
+func (e *External[M, D, PD]) conflict(ctx context.Context, cr M) error {
+	if _, err := e.Observe(ctx, cr); err != nil {
+		return err
+	}
+	return errors.Errorf(errFmtUpdateConflict, e.kind.Metadata(cr).ModifiedBy)
+}
+
+// Delete the settings object of the supplied managed resource.
+func (e *External[M, D, PD]) Delete(ctx context.Context, mg resource.Managed) error {
+	ctx, span := tracing.Start(ctx, "Delete", e.kind.GroupKind, mg)
+	err := e.delete(ctx, mg)
+	tracing.End(span, mg, err)
+	return err
+}
+
+func (e *External[M, D, PD]) delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(M)
+	if !ok {
+		return errors.Errorf(errFmtNotKind, e.kind.GroupKind)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	// Settings objects of validate-only resources were never written.
+	if e.kind.Spec(cr).ValidateOnly {
+		return nil
+	}
+
+	id := meta.GetExternalName(cr)
+	if err := e.checkDependents(cr, id); err != nil {
+		return err
+	}
+
+	err := e.traced(ctx).Delete(id)
+	if settings20.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		e.classify(cr, err)
+		return errors.Wrap(err, errDelete)
+	}
+
+	return nil
+}
+
+// checkDependents returns an error if other settings objects reference the
+// settings object with the supplied ID, unless its deletion is forced.
+func (e *External[M, D, PD]) checkDependents(cr M, id string) error {
+	if e.dependents == nil {
+		return nil
+	}
+	if cr.GetAnnotations()[apisv1alpha1.AnnotationKeyForceDelete] == "true" {
+		cr.SetConditions(apisv1alpha1.DeletionAllowed(apisv1alpha1.ReasonForced))
+		return nil
+	}
+
+	deps, err := e.dependents(id)
+	if err != nil {
+		return errors.Wrap(err, errListDependents)
+	}
+	if len(deps) > 0 {
+		err := errors.Errorf(errFmtDependents, strings.Join(deps, ", "), apisv1alpha1.AnnotationKeyForceDelete)
+		cr.SetConditions(apisv1alpha1.DeletionBlocked(err.Error()))
+		return err
+	}
+
+	cr.SetConditions(apisv1alpha1.DeletionAllowed(apisv1alpha1.ReasonNoDependents))
+	return nil
+}
+
+// validateOnly validates the desired state of the supplied managed resource
+// with the API, as an update of its settings object if that exists, and
+// reports the resource as up to date so that it is never written.
+func (e *External[M, D, PD]) validateOnly(ctx context.Context, cr M, id string) (managed.ExternalObservation, error) {
+	var observed D
+	_, err := e.traced(ctx).GetObject(id, PD(&observed))
+	if settings20.IsNotFound(err) {
+		id = ""
+	} else if err != nil {
+		e.classify(cr, err)
+		return managed.ExternalObservation{}, err
+	}
+
+	desired, err := e.toDTO(cr)
+	if err != nil {
+		cr.SetConditions(xpv1.Unavailable())
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	err = e.traced(ctx).Validate(id, PD(&desired))
+	switch {
+	case settings20.IsInvalid(err):
+		e.classify(cr, err)
+		cr.SetConditions(xpv1.Unavailable())
+	case err != nil:
+		e.classify(cr, err)
+		return managed.ExternalObservation{}, errors.Wrap(err, errValidate)
+	default:
+		cr.SetConditions(xpv1.Available(), apisv1alpha1.APIRequestSucceeded(), apisv1alpha1.Valid())
+	}
+
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+// An unresolvedError is returned by ToDTO if the desired state depends on
+// references that are not resolved yet.
+type unresolvedError struct{ error }
+
+// Unresolved returns the supplied error, which ToDTO returns because
+// references of the managed resource are not resolved yet. Unlike other errors
+// of ToDTO, it does not mark the desired state invalid: the managed reconciler
+// resolves references again before it retries.
+func Unresolved(err error) error {
+	return unresolvedError{err}
+}
+
+// traced returns the settings service, tracing its calls within the supplied
+// context.
+func (e *External[M, D, PD]) traced(ctx context.Context) settings20.CRUDService[PD] {
+	return tracing.NewService(ctx, e.service)
+}
+
+// toDTO converts the desired state of the supplied managed resource into a
+// settings object. A desired state that cannot be converted, e.g. because a
+// parameter required by another is missing, is reported as invalid unless its
+// references are just not resolved yet.
+func (e *External[M, D, PD]) toDTO(cr M) (D, error) {
+	desired, err := e.kind.ToDTO(cr)
+	if err != nil {
+		if ue := (unresolvedError{}); !errors.As(err, &ue) {
+			cr.SetConditions(apisv1alpha1.Invalid(err.Error()))
+		}
+		return desired, errors.Wrap(err, errToDTO)
+	}
+	return desired, nil
+}
+
+// classify records the reason the supplied request failed for in the
+// APIRequest condition of the supplied managed resource and returns it. A
+// desired state rejected as invalid is also recorded in the Validated
+// condition.
+func (e *External[M, D, PD]) classify(cr M, err error) xpv1.ConditionReason {
+	reason := settings20.Reason(err)
+	if reason != "" {
+		cr.SetConditions(apisv1alpha1.APIRequestFailed(reason, settings20.Message(err)))
+	}
+	if reason == apisv1alpha1.ReasonInvalidSpec {
+		cr.SetConditions(apisv1alpha1.Invalid(settings20.Message(err)))
+	}
+	return reason
+}
+
+// failed classifies a failed create or update. If the API rejected the
+// desired state as invalid its generation is recorded, so that it is not sent
+// again, and the status is persisted right away because the managed
+// reconciler discards status changes made by a failed Create.
+func (e *External[M, D, PD]) failed(ctx context.Context, cr M, err error) error {
+	if e.classify(cr, err) != apisv1alpha1.ReasonInvalidSpec {
+		return err
+	}
+
+	e.kind.Metadata(cr).RejectedGeneration = cr.GetGeneration()
+	if err := e.kube.Status().Update(ctx, cr); err != nil {
+		return errors.Wrap(err, errPersistRejection)
+	}
+	return err
+}
+
+// rejected returns true if the API rejected the current generation of the
+// supplied managed resource as invalid, according to the supplied metadata.
+func rejected(md *apisv1alpha1.SettingsObjectObservation, cr resource.Managed) bool {
+	return md.RejectedGeneration != 0 && md.RejectedGeneration == cr.GetGeneration()
+}
@@ -0,0 +1,992 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api"
+	autotagging "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging/settings"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type mockService struct {
+	list     func() (api.Stubs, error)
+	get      func(id string, v *autotagging.Settings) (*settings20.Object, error)
+	create   func(v *autotagging.Settings) (*api.Stub, error)
+	update   func(id string, v *autotagging.Settings, updateToken string) error
+	validate func(id string, v *autotagging.Settings) error
+	delete   func(id string) error
+}
+
+func (m mockService) List() (api.Stubs, error) { return m.list() }
+
+func (m mockService) Get(id string, v *autotagging.Settings) error {
+	_, err := m.get(id, v)
+	return err
+}
+
+func (m mockService) GetObject(id string, v *autotagging.Settings) (*settings20.Object, error) {
+	return m.get(id, v)
+}
+
+func (m mockService) SchemaID() string { return "builtin:tags.auto-tagging" }
+
+func (m mockService) Create(v *autotagging.Settings) (*api.Stub, error) { return m.create(v) }
+
+func (m mockService) Update(id string, v *autotagging.Settings) error { return m.update(id, v, "") }
+
+func (m mockService) UpdateObject(id string, v *autotagging.Settings, updateToken string) error {
+	return m.update(id, v, updateToken)
+}
+
+// Validate accepts every settings object unless validate is set.
+func (m mockService) Validate(id string, v *autotagging.Settings) error {
+	if m.validate == nil {
+		return nil
+	}
+	return m.validate(id, v)
+}
+
+func (m mockService) Delete(id string) error { return m.delete(id) }
+
+func (m mockService) Name() string { return m.SchemaID() }
+
+var _ settings20.CRUDService[*autotagging.Settings] = mockService{}
+
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+var (
+	errUnnamed      = errors.New("spec.forProvider.name is required")
+	errUnresolved   = errors.New("spec.forProvider.name is not resolved")
+	errUnobservable = errors.New("unsupported name")
+)
+
+var testKind = &Kind[*v1alpha1.AutoTag, autotagging.Settings]{
+	GroupKind:        v1alpha1.AutoTagGroupKind,
+	GroupVersionKind: v1alpha1.AutoTagGroupVersionKind,
+	Name:             func(cr *v1alpha1.AutoTag) string { return cr.Spec.ForProvider.Name },
+	ToDTO: func(cr *v1alpha1.AutoTag) (autotagging.Settings, error) {
+		if cr.Spec.ForProvider.Name == "" {
+			return autotagging.Settings{}, errUnnamed
+		}
+		if cr.Spec.ForProvider.Name == "unresolved" {
+			return autotagging.Settings{}, Unresolved(errUnresolved)
+		}
+		return autotagging.Settings{Name: cr.Spec.ForProvider.Name, Description: cr.Spec.ForProvider.Description}, nil
+	},
+	LateInitialize: func(cr *v1alpha1.AutoTag, s autotagging.Settings) bool {
+		if cr.Spec.ForProvider.Description != nil || s.Description == nil {
+			return false
+		}
+		cr.Spec.ForProvider.Description = s.Description
+		return true
+	},
+	Observe: func(cr *v1alpha1.AutoTag, id string, o *settings20.Object, s autotagging.Settings) error {
+		if s.Name == "unobservable" {
+			return errUnobservable
+		}
+		cr.Status.AtProvider = v1alpha1.AutoTagObservation{ID: id, SettingsObjectObservation: o.Observation(), Name: s.Name}
+		return nil
+	},
+	Spec: func(cr *v1alpha1.AutoTag) *apisv1alpha1.SettingsObjectSpec {
+		return &cr.Spec.SettingsObjectSpec
+	},
+	Metadata: func(cr *v1alpha1.AutoTag) *apisv1alpha1.SettingsObjectObservation {
+		return &cr.Status.AtProvider.SettingsObjectObservation
+	},
+}
+
+type autoTagModifier func(cr *v1alpha1.AutoTag)
+
+func autoTag(m ...autoTagModifier) *v1alpha1.AutoTag {
+	cr := &v1alpha1.AutoTag{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cool",
+			Annotations: map[string]string{meta.AnnotationKeyExternalName: "some-id"},
+		},
+		Spec: v1alpha1.AutoTagSpec{
+			ForProvider: v1alpha1.AutoTagParameters{Name: "cool-tag"},
+		},
+	}
+	for _, fn := range m {
+		fn(cr)
+	}
+	return cr
+}
+
+// withoutName unsets the name of the settings object, which testKind cannot
+// convert.
+func withoutName() autoTagModifier {
+	return func(cr *v1alpha1.AutoTag) {
+		cr.Spec.ForProvider.Name = ""
+	}
+}
+
+// withDeletionTimestamp marks the managed resource as deleted.
+func withDeletionTimestamp() autoTagModifier {
+	return func(cr *v1alpha1.AutoTag) {
+		now := metav1.Unix(0, 0)
+		cr.SetDeletionTimestamp(&now)
+	}
+}
+
+// withValidateOnly puts the managed resource into validate-only mode.
+func withValidateOnly() autoTagModifier {
+	return func(cr *v1alpha1.AutoTag) {
+		cr.Spec.ValidateOnly = true
+	}
+}
+
+// withRejected sets the generation of the managed resource and the generation
+// whose desired state the API rejected.
+func withRejected(generation, rejected int64) autoTagModifier {
+	return func(cr *v1alpha1.AutoTag) {
+		cr.SetGeneration(generation)
+		cr.Status.AtProvider.RejectedGeneration = rejected
+	}
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := errors.New("boom")
+	description := "observed"
+
+	type args struct {
+		svc settings20.CRUDService[*autotagging.Settings]
+		mg  resource.Managed
+	}
+
+	type want struct {
+		o      managed.ExternalObservation
+		cr     resource.Managed
+		events []event.Event
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotFound": {
+			reason: "A settings object that does not exist should be reported as such.",
+			args: args{
+				svc: mockService{get: func(_ string, _ *autotagging.Settings) (*settings20.Object, error) {
+					return nil, rest.Error{Code: http.StatusNotFound}
+				}},
+				mg: autoTag(),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(apisv1alpha1.APIRequestSucceeded())
+				}),
+			},
+		},
+		"ValidateOnlyValid": {
+			reason: "A validate-only resource without settings object should be validated as a new object and never be created.",
+			args: args{
+				svc: mockService{
+					get: func(_ string, _ *autotagging.Settings) (*settings20.Object, error) {
+						return nil, rest.Error{Code: http.StatusNotFound}
+					},
+					validate: func(id string, v *autotagging.Settings) error {
+						if id != "" || v.Name != "cool-tag" {
+							t.Errorf("unexpected validation of %q as %q", v.Name, id)
+						}
+						return nil
+					},
+				},
+				mg: autoTag(withValidateOnly()),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cr: autoTag(withValidateOnly(), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Available(), apisv1alpha1.APIRequestSucceeded(), apisv1alpha1.Valid())
+				}),
+			},
+		},
+		"ValidateOnlyInvalid": {
+			reason: "Constraint violations of a validate-only resource should be reported without updating its settings object.",
+			args: args{
+				svc: mockService{
+					get: func(_ string, _ *autotagging.Settings) (*settings20.Object, error) {
+						return &settings20.Object{}, nil
+					},
+					validate: func(id string, _ *autotagging.Settings) error {
+						if id != "some-id" {
+							t.Errorf("unexpected validation as %q", id)
+						}
+						return rest.Error{Code: http.StatusBadRequest, Message: "Invalid"}
+					},
+				},
+				mg: autoTag(withValidateOnly()),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cr: autoTag(withValidateOnly(), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(
+						xpv1.Unavailable(),
+						apisv1alpha1.APIRequestFailed(apisv1alpha1.ReasonInvalidSpec, "Invalid"),
+						apisv1alpha1.Invalid("Invalid"),
+					)
+				}),
+			},
+		},
+		"NotFoundRejected": {
+			reason: "A settings object whose desired state was rejected as invalid should not be created again.",
+			args: args{
+				svc: mockService{get: func(_ string, _ *autotagging.Settings) (*settings20.Object, error) {
+					return nil, rest.Error{Code: http.StatusNotFound}
+				}},
+				mg: autoTag(withRejected(2, 2)),
+			},
+			want: want{
+				cr:  autoTag(withRejected(2, 2)),
+				err: errors.Errorf(errFmtRejected, 2),
+			},
+		},
+		"DeletedNotFoundRejected": {
+			reason: "A rejected desired state should not block the deletion of a resource whose settings object does not exist.",
+			args: args{
+				svc: mockService{get: func(_ string, _ *autotagging.Settings) (*settings20.Object, error) {
+					return nil, rest.Error{Code: http.StatusNotFound}
+				}},
+				mg: autoTag(withRejected(2, 2), withDeletionTimestamp()),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+				cr: autoTag(withRejected(2, 2), withDeletionTimestamp(), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(apisv1alpha1.APIRequestSucceeded())
+				}),
+			},
+		},
+		"NotFoundUnconvertible": {
+			reason: "A desired state that cannot be converted should be reported as invalid before it is created.",
+			args: args{
+				svc: mockService{get: func(_ string, _ *autotagging.Settings) (*settings20.Object, error) {
+					return nil, rest.Error{Code: http.StatusNotFound}
+				}},
+				mg: autoTag(withoutName()),
+			},
+			want: want{
+				cr: autoTag(withoutName(), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(apisv1alpha1.APIRequestSucceeded(), apisv1alpha1.Invalid(errUnnamed.Error()))
+				}),
+				err: errors.Wrap(errUnnamed, errToDTO),
+			},
+		},
+		"NotFoundUnresolved": {
+			reason: "A desired state whose references are not resolved yet should not be reported as invalid.",
+			args: args{
+				svc: mockService{get: func(_ string, _ *autotagging.Settings) (*settings20.Object, error) {
+					return nil, rest.Error{Code: http.StatusNotFound}
+				}},
+				mg: autoTag(func(cr *v1alpha1.AutoTag) { cr.Spec.ForProvider.Name = "unresolved" }),
+			},
+			want: want{
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					cr.Spec.ForProvider.Name = "unresolved"
+					cr.SetConditions(apisv1alpha1.APIRequestSucceeded())
+				}),
+				err: errors.Wrap(Unresolved(errUnresolved), errToDTO),
+			},
+		},
+		"DeletedUnconvertible": {
+			reason: "A desired state that cannot be converted should not block the deletion of an existing settings object.",
+			args: args{
+				svc: mockService{get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+					v.Name = "cool-tag"
+					return &settings20.Object{}, nil
+				}},
+				mg: autoTag(withoutName(), withDeletionTimestamp()),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true},
+				cr: autoTag(withoutName(), withDeletionTimestamp(), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Available(), apisv1alpha1.Invalid(errUnnamed.Error()))
+					cr.Status.AtProvider = v1alpha1.AutoTagObservation{ID: "some-id", Name: "cool-tag"}
+				}),
+			},
+		},
+		"Unobservable": {
+			reason: "An observed state that cannot be recorded should be reported.",
+			args: args{
+				svc: mockService{get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+					v.Name = "unobservable"
+					return &settings20.Object{}, nil
+				}},
+				mg: autoTag(),
+			},
+			want: want{
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Available())
+				}),
+				err: errUnobservable,
+			},
+		},
+		"DeletedUnobservable": {
+			reason: "An observed state that cannot be recorded should not block the deletion of the settings object.",
+			args: args{
+				svc: mockService{get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+					v.Name = "unobservable"
+					return &settings20.Object{}, nil
+				}},
+				mg: autoTag(withDeletionTimestamp()),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true},
+				cr: autoTag(withDeletionTimestamp(), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Available())
+				}),
+			},
+		},
+		"GetError": {
+			reason: "Errors reading the settings object should be returned.",
+			args: args{
+				svc: mockService{get: func(_ string, _ *autotagging.Settings) (*settings20.Object, error) {
+					return nil, errBoom
+				}},
+				mg: autoTag(),
+			},
+			want: want{
+				cr:  autoTag(),
+				err: errBoom,
+			},
+		},
+		"GetThrottled": {
+			reason: "API errors reading the settings object should be classified in the APIRequest condition.",
+			args: args{
+				svc: mockService{get: func(_ string, _ *autotagging.Settings) (*settings20.Object, error) {
+					return nil, rest.Error{Code: http.StatusTooManyRequests, Message: "Too many requests"}
+				}},
+				mg: autoTag(),
+			},
+			want: want{
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(apisv1alpha1.APIRequestFailed(apisv1alpha1.ReasonThrottled, "Too many requests"))
+				}),
+				err: rest.Error{Code: http.StatusTooManyRequests, Message: "Too many requests"},
+			},
+		},
+		"DriftRejected": {
+			reason: "A drifted settings object whose desired state was rejected as invalid should not be updated again.",
+			args: args{
+				svc: mockService{get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+					v.Name = "other-tag"
+					return &settings20.Object{}, nil
+				}},
+				mg: autoTag(withRejected(2, 2)),
+			},
+			want: want{
+				cr: autoTag(withRejected(2, 2), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Available())
+					cr.Status.AtProvider.ID = "some-id"
+					cr.Status.AtProvider.Name = "other-tag"
+					cr.Status.AtProvider.Drift = []string{"name"}
+				}),
+				events: []event.Event{event.Normal(reasonDriftDetected, fmt.Sprintf(msgFmtDriftDetected, "name"))},
+				err:    errors.Errorf(errFmtRejected, 2),
+			},
+		},
+		"DeletedDriftRejected": {
+			reason: "A rejected desired state should not block the deletion of a drifted settings object.",
+			args: args{
+				svc: mockService{get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+					v.Name = "other-tag"
+					return &settings20.Object{}, nil
+				}},
+				mg: autoTag(withRejected(2, 2), withDeletionTimestamp()),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					Diff:             cmp.Diff(autotagging.Settings{Name: "other-tag"}, autotagging.Settings{Name: "cool-tag"}, cmpopts.EquateEmpty()),
+				},
+				cr: autoTag(withRejected(2, 0), withDeletionTimestamp(), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Available(), apisv1alpha1.APIRequestSucceeded())
+					cr.Status.AtProvider.ID = "some-id"
+					cr.Status.AtProvider.Name = "other-tag"
+					cr.Status.AtProvider.Drift = []string{"name"}
+				}),
+				events: []event.Event{event.Normal(reasonDriftDetected, fmt.Sprintf(msgFmtDriftDetected, "name"))},
+			},
+		},
+		"DriftRejectedPreviousGeneration": {
+			reason: "A drifted settings object should be updated once the spec changed since its desired state was rejected.",
+			args: args{
+				svc: mockService{get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+					v.Name = "other-tag"
+					return &settings20.Object{}, nil
+				}},
+				mg: autoTag(withRejected(3, 2)),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					Diff:             cmp.Diff(autotagging.Settings{Name: "other-tag"}, autotagging.Settings{Name: "cool-tag"}, cmpopts.EquateEmpty()),
+				},
+				cr: autoTag(withRejected(3, 0), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Available(), apisv1alpha1.APIRequestSucceeded())
+					cr.Status.AtProvider.ID = "some-id"
+					cr.Status.AtProvider.Name = "other-tag"
+					cr.Status.AtProvider.Drift = []string{"name"}
+				}),
+				events: []event.Event{event.Normal(reasonDriftDetected, fmt.Sprintf(msgFmtDriftDetected, "name"))},
+			},
+		},
+		"UpToDate": {
+			reason: "A settings object matching the desired state should be up to date.",
+			args: args{
+				svc: mockService{get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+					v.Name = "cool-tag"
+					return &settings20.Object{UpdateToken: "token"}, nil
+				}},
+				mg: autoTag(),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Available(), apisv1alpha1.APIRequestSucceeded(), apisv1alpha1.Valid())
+					cr.Status.AtProvider = v1alpha1.AutoTagObservation{
+						ID:                        "some-id",
+						SettingsObjectObservation: apisv1alpha1.SettingsObjectObservation{UpdateToken: "token"},
+						Name:                      "cool-tag",
+					}
+				}),
+			},
+		},
+		"LateInitialized": {
+			reason: "Unset optional parameters should be late-initialized from the settings object.",
+			args: args{
+				svc: mockService{get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+					v.Name = "cool-tag"
+					v.Description = &description
+					return &settings20.Object{}, nil
+				}},
+				mg: autoTag(),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Available(), apisv1alpha1.APIRequestSucceeded(), apisv1alpha1.Valid())
+					cr.Spec.ForProvider.Description = &description
+					cr.Status.AtProvider = v1alpha1.AutoTagObservation{ID: "some-id", Name: "cool-tag"}
+				}),
+			},
+		},
+		"Drift": {
+			reason: "A settings object differing from the desired state should not be up to date.",
+			args: args{
+				svc: mockService{get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+					v.Name = "other-tag"
+					return &settings20.Object{}, nil
+				}},
+				mg: autoTag(),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					Diff:             cmp.Diff(autotagging.Settings{Name: "other-tag"}, autotagging.Settings{Name: "cool-tag"}, cmpopts.EquateEmpty()),
+				},
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Available(), apisv1alpha1.APIRequestSucceeded())
+					cr.Status.AtProvider = v1alpha1.AutoTagObservation{
+						ID:                        "some-id",
+						SettingsObjectObservation: apisv1alpha1.SettingsObjectObservation{Drift: []string{"name"}},
+						Name:                      "other-tag",
+					}
+				}),
+				events: []event.Event{event.Normal(reasonDriftDetected, fmt.Sprintf(msgFmtDriftDetected, "name"))},
+			},
+		},
+		"DriftUnchanged": {
+			reason: "Drift that was already reported should be recorded in status without emitting another event.",
+			args: args{
+				svc: mockService{get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+					v.Name = "other-tag"
+					return &settings20.Object{}, nil
+				}},
+				mg: autoTag(func(cr *v1alpha1.AutoTag) {
+					cr.Status.AtProvider.Drift = []string{"name"}
+				}),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					Diff:             cmp.Diff(autotagging.Settings{Name: "other-tag"}, autotagging.Settings{Name: "cool-tag"}, cmpopts.EquateEmpty()),
+				},
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Available(), apisv1alpha1.APIRequestSucceeded())
+					cr.Status.AtProvider = v1alpha1.AutoTagObservation{
+						ID:                        "some-id",
+						SettingsObjectObservation: apisv1alpha1.SettingsObjectObservation{Drift: []string{"name"}},
+						Name:                      "other-tag",
+					}
+				}),
+			},
+		},
+		"DriftIgnored": {
+			reason: "Differences at paths listed in the ignore-drift annotation should not be considered drift.",
+			args: args{
+				svc: mockService{get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+					v.Name = "other-tag"
+					return &settings20.Object{}, nil
+				}},
+				mg: autoTag(func(cr *v1alpha1.AutoTag) {
+					meta.AddAnnotations(cr, map[string]string{apisv1alpha1.AnnotationKeyIgnoreDrift: "description, name"})
+				}),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					meta.AddAnnotations(cr, map[string]string{apisv1alpha1.AnnotationKeyIgnoreDrift: "description, name"})
+					cr.SetConditions(xpv1.Available(), apisv1alpha1.APIRequestSucceeded(), apisv1alpha1.Valid())
+					cr.Status.AtProvider = v1alpha1.AutoTagObservation{ID: "some-id", Name: "other-tag"}
+				}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{}
+			e := NewExternal(testKind, tc.args.svc)
+			e.recorder = r
+			got, err := e.Observe(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.events); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	errBoom := errors.New("boom")
+	errInvalid := rest.Error{
+		Code:                 http.StatusBadRequest,
+		Message:              "Constraints violated.",
+		ConstraintViolations: []rest.ConstraintViolation{{Path: "name", Message: "must be unique"}},
+	}
+
+	type args struct {
+		kube client.Client
+		svc  settings20.CRUDService[*autotagging.Settings]
+		mg   resource.Managed
+	}
+
+	type want struct {
+		cr  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "The ID of the created settings object should become the external name.",
+			args: args{
+				svc: mockService{create: func(_ *autotagging.Settings) (*api.Stub, error) {
+					return &api.Stub{ID: "new-id"}, nil
+				}},
+				mg: autoTag(),
+			},
+			want: want{
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					meta.SetExternalName(cr, "new-id")
+					cr.SetConditions(xpv1.Creating(), apisv1alpha1.Valid(), apisv1alpha1.APIRequestSucceeded())
+				}),
+			},
+		},
+		"Unconvertible": {
+			reason: "A desired state that cannot be converted should be reported as invalid and never sent.",
+			args: args{
+				svc: mockService{},
+				mg:  autoTag(withoutName()),
+			},
+			want: want{
+				cr: autoTag(withoutName(), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Creating(), apisv1alpha1.Invalid(errUnnamed.Error()))
+				}),
+				err: errors.Wrap(errUnnamed, errToDTO),
+			},
+		},
+		"Error": {
+			reason: "Errors creating the settings object should be returned.",
+			args: args{
+				svc: mockService{create: func(_ *autotagging.Settings) (*api.Stub, error) {
+					return nil, errBoom
+				}},
+				mg: autoTag(),
+			},
+			want: want{
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Creating(), apisv1alpha1.Valid())
+				}),
+				err: errors.Wrap(errBoom, errCreate),
+			},
+		},
+		"Forbidden": {
+			reason: "A missing token scope should be classified without recording a rejection.",
+			args: args{
+				svc: mockService{create: func(_ *autotagging.Settings) (*api.Stub, error) {
+					return nil, rest.Error{Code: http.StatusForbidden, Message: "Token is missing required scope"}
+				}},
+				mg: autoTag(),
+			},
+			want: want{
+				cr: autoTag(func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(xpv1.Creating(), apisv1alpha1.Valid(), apisv1alpha1.APIRequestFailed(apisv1alpha1.ReasonForbidden, "Token is missing required scope"))
+				}),
+				err: errors.Wrap(rest.Error{Code: http.StatusForbidden, Message: "Token is missing required scope"}, errCreate),
+			},
+		},
+		"Invalid": {
+			reason: "A desired state failing validation should be recorded with its constraint violations and persisted right away.",
+			args: args{
+				kube: &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil)},
+				svc: mockService{validate: func(id string, _ *autotagging.Settings) error {
+					if id != "" {
+						t.Errorf("unexpected ID %q", id)
+					}
+					return errInvalid
+				}},
+				mg: autoTag(withRejected(2, 0)),
+			},
+			want: want{
+				cr: autoTag(withRejected(2, 2), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(
+						xpv1.Creating(),
+						apisv1alpha1.APIRequestFailed(apisv1alpha1.ReasonInvalidSpec, "Constraints violated.\nname: must be unique"),
+						apisv1alpha1.Invalid("Constraints violated.\nname: must be unique"),
+					)
+				}),
+				err: errors.Wrap(errInvalid, errValidate),
+			},
+		},
+		"InvalidPersistError": {
+			reason: "Errors persisting a rejection should be returned.",
+			args: args{
+				kube: &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom)},
+				svc: mockService{validate: func(id string, _ *autotagging.Settings) error {
+					if id != "" {
+						t.Errorf("unexpected ID %q", id)
+					}
+					return errInvalid
+				}},
+				mg: autoTag(withRejected(2, 0)),
+			},
+			want: want{
+				cr: autoTag(withRejected(2, 2), func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(
+						xpv1.Creating(),
+						apisv1alpha1.APIRequestFailed(apisv1alpha1.ReasonInvalidSpec, "Constraints violated.\nname: must be unique"),
+						apisv1alpha1.Invalid("Constraints violated.\nname: must be unique"),
+					)
+				}),
+				err: errors.Wrap(errBoom, errPersistRejection),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := NewExternal(testKind, tc.args.svc)
+			e.kube = tc.args.kube
+			_, err := e.Create(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	withToken := func(cr *v1alpha1.AutoTag) {
+		cr.Status.AtProvider.UpdateToken = "observed"
+	}
+
+	type args struct {
+		kube client.Client
+		svc  settings20.CRUDService[*autotagging.Settings]
+		mg   resource.Managed
+	}
+
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "The observed update token should be sent with the update.",
+			args: args{
+				svc: mockService{update: func(_ string, _ *autotagging.Settings, updateToken string) error {
+					if updateToken != "observed" {
+						t.Errorf("unexpected update token %q", updateToken)
+					}
+					return nil
+				}},
+				mg: autoTag(withToken),
+			},
+			want: want{},
+		},
+		"Conflict": {
+			reason: "A concurrent modification should be re-observed and reported rather than overwritten.",
+			args: args{
+				svc: mockService{
+					update: func(_ string, _ *autotagging.Settings, _ string) error {
+						return rest.Error{Code: http.StatusConflict}
+					},
+					get: func(_ string, v *autotagging.Settings) (*settings20.Object, error) {
+						v.Name = "ui-tag"
+						return &settings20.Object{ModifiedBy: "alice", UpdateToken: "new"}, nil
+					},
+				},
+				mg: autoTag(withToken),
+			},
+			want: want{err: errors.Errorf(errFmtUpdateConflict, "alice")},
+		},
+		"Error": {
+			reason: "Other errors updating the settings object should be returned.",
+			args: args{
+				svc: mockService{update: func(_ string, _ *autotagging.Settings, _ string) error {
+					return errBoom
+				}},
+				mg: autoTag(withToken),
+			},
+			want: want{err: errors.Wrap(errBoom, errUpdate)},
+		},
+		"ServerError": {
+			reason: "Server errors should be returned so that the update is retried.",
+			args: args{
+				svc: mockService{update: func(_ string, _ *autotagging.Settings, _ string) error {
+					return rest.Error{Code: http.StatusServiceUnavailable}
+				}},
+				mg: autoTag(withToken),
+			},
+			want: want{err: errors.Wrap(rest.Error{Code: http.StatusServiceUnavailable}, errUpdate)},
+		},
+		"Invalid": {
+			reason: "A desired state rejected as invalid should be recorded so that it is not sent again.",
+			args: args{
+				kube: &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(obj client.Object) error {
+					if got := obj.(*v1alpha1.AutoTag).Status.AtProvider.RejectedGeneration; got != 2 {
+						t.Errorf("unexpected rejected generation %d", got)
+					}
+					return nil
+				})},
+				svc: mockService{update: func(_ string, _ *autotagging.Settings, _ string) error {
+					return rest.Error{Code: http.StatusBadRequest}
+				}},
+				mg: autoTag(withToken, withRejected(2, 0)),
+			},
+			want: want{err: errors.Wrap(rest.Error{Code: http.StatusBadRequest}, errUpdate)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := NewExternal(testKind, tc.args.svc)
+			e.kube = tc.args.kube
+			_, err := e.Update(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := errors.New("boom")
+	dependent := "EMAIL notification \"cool-notification\" (other-id)"
+	errDependents := errors.Errorf(errFmtDependents, dependent, apisv1alpha1.AnnotationKeyForceDelete)
+	deleting := func(cr *v1alpha1.AutoTag) { cr.SetConditions(xpv1.Deleting()) }
+	forced := func(cr *v1alpha1.AutoTag) {
+		meta.AddAnnotations(cr, map[string]string{apisv1alpha1.AnnotationKeyForceDelete: "true"})
+	}
+
+	type args struct {
+		svc        settings20.CRUDService[*autotagging.Settings]
+		dependents func(id string) ([]string, error)
+		mg         resource.Managed
+	}
+
+	type want struct {
+		cr  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "Deleting a settings object should not return an error.",
+			args: args{
+				svc: mockService{delete: func(_ string) error { return nil }},
+				mg:  autoTag(),
+			},
+			want: want{cr: autoTag(deleting)},
+		},
+		"AlreadyGone": {
+			reason: "A settings object that is already gone should count as deleted.",
+			args: args{
+				svc: mockService{delete: func(_ string) error { return rest.Error{Code: http.StatusNotFound} }},
+				mg:  autoTag(),
+			},
+			want: want{cr: autoTag(deleting)},
+		},
+		"Error": {
+			reason: "Other errors deleting the settings object should be returned.",
+			args: args{
+				svc: mockService{delete: func(_ string) error { return errBoom }},
+				mg:  autoTag(),
+			},
+			want: want{cr: autoTag(deleting), err: errors.Wrap(errBoom, errDelete)},
+		},
+		"ValidateOnly": {
+			reason: "The settings object of a validate-only resource should never be deleted.",
+			args: args{
+				svc: mockService{},
+				mg:  autoTag(withValidateOnly()),
+			},
+			want: want{cr: autoTag(withValidateOnly(), deleting)},
+		},
+		"NoDependents": {
+			reason: "A settings object no other settings object references should be deleted.",
+			args: args{
+				svc: mockService{delete: func(_ string) error { return nil }},
+				dependents: func(id string) ([]string, error) {
+					if id != "some-id" {
+						return nil, errors.Errorf("dependents of %q listed", id)
+					}
+					return nil, nil
+				},
+				mg: autoTag(),
+			},
+			want: want{cr: autoTag(deleting, func(cr *v1alpha1.AutoTag) {
+				cr.SetConditions(apisv1alpha1.DeletionAllowed(apisv1alpha1.ReasonNoDependents))
+			})},
+		},
+		"HasDependents": {
+			reason: "A settings object other settings objects reference should not be deleted, and why should be reported.",
+			args: args{
+				svc:        mockService{},
+				dependents: func(_ string) ([]string, error) { return []string{dependent}, nil },
+				mg:         autoTag(),
+			},
+			want: want{
+				cr: autoTag(deleting, func(cr *v1alpha1.AutoTag) {
+					cr.SetConditions(apisv1alpha1.DeletionBlocked(errDependents.Error()))
+				}),
+				err: errDependents,
+			},
+		},
+		"Forced": {
+			reason: "A settings object other settings objects reference should be deleted if the deletion is forced.",
+			args: args{
+				svc:        mockService{delete: func(_ string) error { return nil }},
+				dependents: func(_ string) ([]string, error) { return []string{dependent}, nil },
+				mg:         autoTag(forced),
+			},
+			want: want{cr: autoTag(forced, deleting, func(cr *v1alpha1.AutoTag) {
+				cr.SetConditions(apisv1alpha1.DeletionAllowed(apisv1alpha1.ReasonForced))
+			})},
+		},
+		"DependentsError": {
+			reason: "Errors listing the settings objects referencing the settings object should be returned.",
+			args: args{
+				svc:        mockService{},
+				dependents: func(_ string) ([]string, error) { return nil, errBoom },
+				mg:         autoTag(),
+			},
+			want: want{cr: autoTag(deleting), err: errors.Wrap(errBoom, errListDependents)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := NewExternal(testKind, tc.args.svc)
+			e.dependents = tc.args.dependents
+			err := e.Delete(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
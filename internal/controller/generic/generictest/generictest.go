@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package generictest reconciles managed resources of generic kinds in tests,
+// using the managed reconciler of crossplane-runtime, an in-memory Kubernetes
+// API and a fake Settings API, without network access.
+package generictest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-dynatrace/apis"
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20/fake"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+)
+
+const (
+	// ProviderConfigName is the name of the ProviderConfig managed resources
+	// reference by default.
+	ProviderConfigName = "default"
+
+	credentialsNamespace = "crossplane-system"
+	credentialsName      = "dynatrace-creds"
+	credentialsKey       = "credentials"
+)
+
+// An Environment reconciles managed resources of a single kind against a fake
+// Settings API. Its ProviderConfig connects to the fake.
+type Environment struct {
+	// Kube is the in-memory Kubernetes API.
+	Kube client.Client
+
+	// API is the fake Settings API.
+	API *fake.Server
+
+	reconciler reconcile.Reconciler
+}
+
+// New returns an Environment that reconciles managed resources of the supplied
+// kind, using newService to connect to the fake Settings API. The fake is
+// closed when the test finishes.
+func New[M resource.Managed, D any, PD generic.Settings[D]](t *testing.T, k *generic.Kind[M, D], newService func(creds []byte) (settings20.CRUDService[PD], error)) *Environment {
+	t.Helper()
+
+	api := fake.NewServer()
+	t.Cleanup(api.Close)
+
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("clientgoscheme.AddToScheme(...): %v", err)
+	}
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatalf("apis.AddToScheme(...): %v", err)
+	}
+
+	mg, err := s.New(k.GroupVersionKind)
+	if err != nil {
+		t.Fatalf("s.New(%s): %v", k.GroupVersionKind, err)
+	}
+
+	kube := kfake.NewClientBuilder().
+		WithScheme(s).
+		WithStatusSubresource(mg.(client.Object), &apisv1alpha1.ProviderConfig{}).
+		WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: credentialsNamespace, Name: credentialsName},
+				Data:       map[string][]byte{credentialsKey: api.Credentials()},
+			},
+			&apisv1alpha1.ProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: ProviderConfigName},
+				Spec: apisv1alpha1.ProviderConfigSpec{Credentials: apisv1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Namespace: credentialsNamespace, Name: credentialsName},
+						Key:             credentialsKey,
+					}},
+				}},
+			},
+		).
+		Build()
+
+	mgr := &xpfake.Manager{Client: kube, Scheme: s}
+	r := managed.NewReconciler(mgr, resource.ManagedKind(k.GroupVersionKind),
+		managed.WithExternalConnecter(generic.NewConnector[M, D, PD](
+			kube,
+			resource.NewProviderConfigUsageTracker(kube, &apisv1alpha1.ProviderConfigUsage{}),
+			event.NewNopRecorder(),
+			k,
+			newService)),
+		managed.WithLogger(logging.NewNopLogger()),
+		managed.WithRecorder(event.NewNopRecorder()),
+		managed.WithPollInterval(time.Minute),
+		// The fake is consistent, so a settings object that was just
+		// created and cannot be found is really gone.
+		managed.WithCreationGracePeriod(0),
+	)
+
+	return &Environment{Kube: kube, API: api, reconciler: r}
+}
+
+// Create the supplied managed resource. It references the default
+// ProviderConfig unless it references another one.
+func (e *Environment) Create(t *testing.T, mg resource.Managed) {
+	t.Helper()
+
+	if mg.GetProviderConfigReference() == nil {
+		mg.SetProviderConfigReference(&xpv1.Reference{Name: ProviderConfigName})
+	}
+	// The in-memory API does not assign UIDs, which ProviderConfig usages
+	// are named after.
+	if mg.GetUID() == "" {
+		mg.SetUID(types.UID("uid-" + mg.GetName()))
+	}
+	if err := e.Kube.Create(context.Background(), mg); err != nil {
+		t.Fatalf("Create(%s): %v", mg.GetName(), err)
+	}
+}
+
+// Update the spec and metadata of the supplied managed resource.
+func (e *Environment) Update(t *testing.T, mg resource.Managed) {
+	t.Helper()
+
+	if err := e.Kube.Update(context.Background(), mg); err != nil {
+		t.Fatalf("Update(%s): %v", mg.GetName(), err)
+	}
+}
+
+// Delete the supplied managed resource. Its finalizer keeps it around until
+// it is reconciled.
+func (e *Environment) Delete(t *testing.T, mg resource.Managed) {
+	t.Helper()
+
+	if err := e.Kube.Delete(context.Background(), mg); err != nil {
+		t.Fatalf("Delete(%s): %v", mg.GetName(), err)
+	}
+}
+
+// Reconcile the supplied managed resource once and refresh it with its
+// reconciled state. It returns false if the managed resource no longer
+// exists, in which case it is left unchanged.
+func (e *Environment) Reconcile(t *testing.T, mg resource.Managed) bool {
+	t.Helper()
+
+	nn := types.NamespacedName{Name: mg.GetName()}
+	if _, err := e.reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: nn}); err != nil {
+		t.Fatalf("Reconcile(%s): %v", mg.GetName(), err)
+	}
+
+	err := e.Kube.Get(context.Background(), nn, mg)
+	if kerrors.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		t.Fatalf("Get(%s): %v", mg.GetName(), err)
+	}
+	return true
+}
+
+// Value unmarshals the value of the settings object with the supplied ID into
+// v. It fails the test if there is no such object.
+func (e *Environment) Value(t *testing.T, id string, v any) {
+	t.Helper()
+
+	o, ok := e.API.Object(id)
+	if !ok {
+		t.Fatalf("settings object %q does not exist", id)
+	}
+	if err := json.Unmarshal(o.Value, v); err != nil {
+		t.Fatalf("json.Unmarshal(...): %v", err)
+	}
+}
+
+// CheckCondition checks that the supplied managed resource has a condition of
+// the type, status and reason of the supplied one.
+func CheckCondition(t *testing.T, mg resource.Managed, want xpv1.Condition) {
+	t.Helper()
+
+	got := mg.GetCondition(want.Type)
+	if got.Status != want.Status || got.Reason != want.Reason {
+		t.Errorf("%s condition: want %s (%s), got %s (%s): %s", want.Type, want.Status, want.Reason, got.Status, got.Reason, got.Message)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/drift"
+)
+
+// An Action the controller of a managed resource would take on its settings
+// object.
+type Action string
+
+// Actions.
+const (
+	ActionNone   Action = "none"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Reasons no action would be taken.
+const (
+	reasonUpToDate     = "the settings object is up to date"
+	reasonNotFound     = "the settings object does not exist"
+	reasonPaused       = "reconciliation is paused"
+	reasonValidateOnly = "the desired state is only validated"
+	reasonNoCreate     = "the management policies do not allow creating the settings object"
+	reasonNoUpdate     = "the management policies do not allow updating the settings object"
+	reasonNoDelete     = "the deletion or management policies do not allow deleting the settings object"
+)
+
+// A Change the controller of a managed resource would make to its settings
+// object.
+type Change struct {
+	// Action that would be taken.
+	Action Action
+
+	// ID of the settings object, if it exists.
+	ID string
+
+	// Drift lists the paths of the properties that would be written.
+	Drift []string
+
+	// Diff between the observed and the desired settings object.
+	Diff string
+
+	// Reason no action would be taken.
+	Reason string
+}
+
+// A Planner plans the changes the controller of a kind would make to the
+// settings objects of its managed resources, without making them.
+type Planner interface {
+	// GroupKind of the planned managed resources.
+	GroupKind() string
+
+	// Plan the change the controller would make to the settings object of
+	// the supplied managed resource, or to delete it if deleted is true,
+	// using the supplied credentials. The managed resource is observed like
+	// its controller observes it, which updates its status.
+	Plan(ctx context.Context, creds []byte, mg resource.Managed, deleted bool) (Change, error)
+}
+
+// NewPlanner returns a Planner for the supplied kind, using newService to
+// create the settings service for a set of credentials.
+func NewPlanner[M resource.Managed, D any, PD Settings[D]](k *Kind[M, D], newService func(creds []byte) (settings20.CRUDService[PD], error)) Planner {
+	return &planner[M, D, PD]{kind: k, newServiceFn: newService}
+}
+
+type planner[M resource.Managed, D any, PD Settings[D]] struct {
+	kind         *Kind[M, D]
+	newServiceFn func(creds []byte) (settings20.CRUDService[PD], error)
+}
+
+func (p *planner[M, D, PD]) GroupKind() string {
+	return p.kind.GroupKind
+}
+
+func (p *planner[M, D, PD]) Plan(ctx context.Context, creds []byte, mg resource.Managed, deleted bool) (Change, error) {
+	cr, ok := mg.(M)
+	if !ok {
+		return Change{}, errors.Errorf(errFmtNotKind, p.kind.GroupKind)
+	}
+	if meta.IsPaused(cr) {
+		return Change{Action: ActionNone, Reason: reasonPaused}, nil
+	}
+
+	svc, err := p.newServiceFn(creds)
+	if err != nil {
+		return Change{}, errors.Wrap(err, errNewClient)
+	}
+	e := NewExternal[M, D, PD](p.kind, svc)
+	if p.kind.Dependents != nil {
+		e.dependents = func(id string) ([]string, error) { return p.kind.Dependents(creds, id) }
+	}
+
+	// The managed reconciler defaults the external name to the name.
+	if meta.GetExternalName(cr) == "" {
+		meta.SetExternalName(cr, cr.GetName())
+	}
+	// A deleted managed resource is observed like during its deletion.
+	if deleted {
+		now := metav1.Now()
+		cr.SetDeletionTimestamp(&now)
+	}
+
+	o, err := e.Observe(ctx, cr)
+	if err != nil {
+		return Change{}, err
+	}
+	id := meta.GetExternalName(cr)
+	policies := managed.NewManagementPoliciesResolver(true, managementPolicies(cr), deletionPolicy(cr))
+
+	switch {
+	case p.kind.Spec(cr).ValidateOnly:
+		if c := cr.GetCondition(apisv1alpha1.TypeValidated); c.Status == corev1.ConditionFalse {
+			return Change{}, errors.New(c.Message)
+		}
+		return Change{Action: ActionNone, Reason: reasonValidateOnly}, nil
+	case deleted && !o.ResourceExists:
+		return Change{Action: ActionNone, Reason: reasonNotFound}, nil
+	case deleted && !policies.ShouldDelete():
+		return Change{Action: ActionNone, ID: id, Reason: reasonNoDelete}, nil
+	case deleted:
+		if err := e.checkDependents(cr, id); err != nil {
+			return Change{}, err
+		}
+		return Change{Action: ActionDelete, ID: id}, nil
+	case !o.ResourceExists:
+		desired, err := e.toDTO(cr)
+		if err != nil {
+			return Change{}, err
+		}
+		if !policies.ShouldCreate() {
+			return Change{Action: ActionNone, Reason: reasonNoCreate}, nil
+		}
+		// Everything is written on creation, except what the kind never
+		// compares, e.g. secrets.
+		var none D
+		d := drift.Compare(none, desired, p.kind.DiffOptions...)
+		return Change{Action: ActionCreate, Drift: d.Paths, Diff: d.Diff}, nil
+	case !o.ResourceUpToDate && !policies.ShouldUpdate():
+		return Change{Action: ActionNone, ID: id, Drift: p.kind.Metadata(cr).Drift, Diff: o.Diff, Reason: reasonNoUpdate}, nil
+	case !o.ResourceUpToDate:
+		return Change{Action: ActionUpdate, ID: id, Drift: p.kind.Metadata(cr).Drift, Diff: o.Diff}, nil
+	default:
+		return Change{Action: ActionNone, ID: id, Reason: reasonUpToDate}, nil
+	}
+}
+
+// managementPolicies returns the management policies of the supplied managed
+// resource, defaulted like its CRD defaults them.
+func managementPolicies(mg resource.Managed) xpv1.ManagementPolicies {
+	if p := mg.GetManagementPolicies(); len(p) > 0 {
+		return p
+	}
+	return xpv1.ManagementPolicies{xpv1.ManagementActionAll}
+}
+
+// deletionPolicy returns the deletion policy of the supplied managed resource,
+// defaulted like its CRD defaults it.
+func deletionPolicy(mg resource.Managed) xpv1.DeletionPolicy {
+	if p := mg.GetDeletionPolicy(); p != "" {
+		return p
+	}
+	return xpv1.DeletionDelete
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/features"
+	"github.com/crossplane/provider-dynatrace/internal/metrics"
+	"github.com/crossplane/provider-dynatrace/internal/tracing"
+)
+
+const (
+	errNewObject      = "cannot create new managed resource object"
+	errFmtNotAnObject = "%s is not a Kubernetes object"
+)
+
+// Setup adds a controller that reconciles managed resources of the supplied
+// kind, using newService to create the settings service for a set of
+// credentials. Calls of the settings services are recorded as metrics, and
+// reconciles are traced.
+func Setup[M resource.Managed, D any, PD Settings[D]](mgr ctrl.Manager, o controller.Options, k *Kind[M, D], newService func(creds []byte) (settings20.CRUDService[PD], error)) error {
+	name := managed.ControllerName(k.GroupKind)
+
+	obj, err := newObject(mgr, k)
+	if err != nil {
+		return err
+	}
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(NewConnector[M, D, PD](
+			mgr.GetClient(),
+			resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			recorder,
+			k,
+			metrics.Instrument(k.GroupKind, newService))),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...),
+		managed.WithReferenceResolver(tracing.NewReferenceResolver(k.GroupKind, managed.NewAPISimpleReferenceResolver(mgr.GetClient()))),
+	}
+
+	if o.Features.Enabled(features.EnableAlphaManagementPolicies) {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(k.GroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(obj).
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(k.GroupKind, r), o.GlobalRateLimiter))
+}
+
+// newObject returns a new, empty managed resource of the supplied kind.
+func newObject[M resource.Managed, D any](mgr ctrl.Manager, k *Kind[M, D]) (client.Object, error) {
+	ro, err := mgr.GetScheme().New(k.GroupVersionKind)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewObject)
+	}
+	obj, ok := ro.(client.Object)
+	if !ok {
+		return nil, errors.Errorf(errFmtNotAnObject, k.GroupKind)
+	}
+	return obj, nil
+}
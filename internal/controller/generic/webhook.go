@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	xpwebhook "github.com/crossplane/crossplane-runtime/pkg/webhook"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupWebhook adds a webhook that rejects managed resources of the supplied
+// kind that violate the invariants checked by its Validate function.
+func SetupWebhook[M resource.Managed, D any](mgr ctrl.Manager, k *Kind[M, D]) error {
+	obj, err := newObject(mgr, k)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(obj).
+		WithValidator(NewValidator(k)).
+		Complete()
+}
+
+// NewValidator returns a validator for managed resources of the supplied
+// kind. Updates are only rejected if they introduce new violations, so that
+// resources created before an invariant was checked can still be updated,
+// e.g. to remove their finalizer. Warnings are returned whether or not the
+// resource is rejected.
+func NewValidator[M resource.Managed, D any](k *Kind[M, D]) *xpwebhook.Validator {
+	return xpwebhook.NewValidator(
+		xpwebhook.WithValidateCreationFns(func(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+			cr, errs, err := k.validate(obj)
+			if err != nil {
+				return nil, err
+			}
+			if len(errs) == 0 {
+				return k.warnings(cr), nil
+			}
+			return k.warnings(cr), kerrors.NewInvalid(k.GroupVersionKind.GroupKind(), cr.GetName(), errs)
+		}),
+		xpwebhook.WithValidateUpdateFns(func(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+			cr, errs, err := k.validate(newObj)
+			if err != nil {
+				return nil, err
+			}
+			if len(errs) == 0 {
+				return k.warnings(cr), nil
+			}
+			if _, old, err := k.validate(oldObj); err == nil && cmp.Equal(old, errs) {
+				return k.warnings(cr), nil
+			}
+			return k.warnings(cr), kerrors.NewInvalid(k.GroupVersionKind.GroupKind(), cr.GetName(), errs)
+		}),
+	)
+}
+
+// validate returns the invariants the supplied object violates.
+func (k *Kind[M, D]) validate(obj runtime.Object) (M, field.ErrorList, error) {
+	cr, ok := obj.(M)
+	if !ok {
+		return cr, nil, errors.Errorf(errFmtNotKind, k.GroupKind)
+	}
+	if k.Validate == nil {
+		return cr, nil, nil
+	}
+	return cr, k.Validate(cr), nil
+}
+
+// warnings returns the warnings about the supplied managed resource.
+func (k *Kind[M, D]) warnings(cr M) admission.Warnings {
+	if k.Warnings == nil {
+		return nil
+	}
+	return k.Warnings(cr)
+}
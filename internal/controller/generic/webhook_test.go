@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+)
+
+func TestValidator(t *testing.T) {
+	k := *testKind
+	k.Validate = func(cr *v1alpha1.AutoTag) field.ErrorList {
+		if cr.Spec.ForProvider.Name == "" {
+			return field.ErrorList{field.Required(field.NewPath("spec", "forProvider", "name"), "")}
+		}
+		return nil
+	}
+	k.Warnings = func(cr *v1alpha1.AutoTag) []string {
+		if cr.Spec.ForProvider.Description != nil {
+			return []string{"spec.forProvider.description is deprecated"}
+		}
+		return nil
+	}
+	invalid := kerrors.NewInvalid(v1alpha1.AutoTagGroupVersionKind.GroupKind(), "cool",
+		field.ErrorList{field.Required(field.NewPath("spec", "forProvider", "name"), "")})
+	unnamed := func(cr *v1alpha1.AutoTag) { cr.Spec.ForProvider.Name = "" }
+	described := func(cr *v1alpha1.AutoTag) {
+		d := "cool"
+		cr.Spec.ForProvider.Description = &d
+	}
+	deprecated := admission.Warnings{"spec.forProvider.description is deprecated"}
+
+	type args struct {
+		old *v1alpha1.AutoTag
+		cr  *v1alpha1.AutoTag
+	}
+
+	type want struct {
+		warnings admission.Warnings
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CreateValid": {
+			reason: "Creating a resource that violates no invariants should be allowed.",
+			args:   args{cr: autoTag()},
+		},
+		"CreateInvalid": {
+			reason: "Creating a resource that violates an invariant should be rejected.",
+			args:   args{cr: autoTag(unnamed)},
+			want:   want{err: invalid},
+		},
+		"UpdateInvalid": {
+			reason: "Updates that introduce violations should be rejected.",
+			args:   args{old: autoTag(), cr: autoTag(unnamed)},
+			want:   want{err: invalid},
+		},
+		"UpdateAlreadyInvalid": {
+			reason: "Updates of resources that already violated the same invariants should be allowed.",
+			args:   args{old: autoTag(unnamed), cr: autoTag(unnamed, withValidateOnly())},
+		},
+		"CreateWarning": {
+			reason: "Creating a resource with deprecated parameters should be allowed with a warning.",
+			args:   args{cr: autoTag(described)},
+			want:   want{warnings: deprecated},
+		},
+		"CreateInvalidWarning": {
+			reason: "Warnings should be returned even if the resource is rejected.",
+			args:   args{cr: autoTag(unnamed, described)},
+			want:   want{warnings: deprecated, err: invalid},
+		},
+		"UpdateWarning": {
+			reason: "Updating a resource with deprecated parameters should be allowed with a warning.",
+			args:   args{old: autoTag(), cr: autoTag(described)},
+			want:   want{warnings: deprecated},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewValidator(&k)
+			var warnings admission.Warnings
+			var err error
+			if tc.args.old == nil {
+				warnings, err = v.ValidateCreate(context.Background(), tc.args.cr)
+			} else {
+				warnings, err = v.ValidateUpdate(context.Background(), tc.args.old, tc.args.cr)
+			}
+			if diff := cmp.Diff(tc.want.warnings, warnings, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want warnings, +got warnings:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
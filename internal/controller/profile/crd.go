@@ -0,0 +1,465 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	profileSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile/settings"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-dynatrace/apis/alerting/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+	"github.com/crossplane/provider-dynatrace/internal/lateinit"
+)
+
+const (
+	errFmtNoPredefinedFilter = "spec.forProvider.eventFilters[%d].predefinedFilter is required for event filters of type PREDEFINED"
+	errFmtNoCustomFilter     = "spec.forProvider.eventFilters[%d].customFilter is required for event filters of type CUSTOM"
+	errFmtUnsupportedValue   = "unsupported %s %q"
+	errFmtTagRefsUnresolved  = "spec.forProvider.severityRules[%d].tagFilterRefs are not resolved"
+
+	msgFmtOmittedSeverityRule = "severityRules[%d]: %s"
+	msgFmtOmittedEventFilter  = "eventFilters[%d]: %s"
+)
+
+// An enum maps the values of an enum of the Profile API to the values of the
+// corresponding enum of the alerting profile schema. Values are converted
+// explicitly, so that a value only one side knows is reported rather than
+// sent or observed as is.
+type enum[A, D ~string] struct {
+	name   string
+	values map[A]D
+}
+
+func (e enum[A, D]) toDTO(v A) (D, error) {
+	if v == "" {
+		return "", nil
+	}
+	d, ok := e.values[v]
+	if !ok {
+		return "", errors.Errorf(errFmtUnsupportedValue, e.name, v)
+	}
+	return d, nil
+}
+
+func (e enum[A, D]) fromDTO(v D) (A, error) {
+	if v == "" {
+		return "", nil
+	}
+	for a, d := range e.values {
+		if d == v {
+			return a, nil
+		}
+	}
+	return "", errors.Errorf(errFmtUnsupportedValue, e.name, v)
+}
+
+// identity maps each of the supplied values to the value of the same name.
+func identity[A, D ~string](values []A) map[A]D {
+	m := make(map[A]D, len(values))
+	for _, v := range values {
+		m[v] = D(v)
+	}
+	return m
+}
+
+var (
+	severityLevels = enum[v1alpha1.SeverityLevel, profileSettings.SeverityLevel]{
+		name: "severity level",
+		values: map[v1alpha1.SeverityLevel]profileSettings.SeverityLevel{
+			v1alpha1.SeverityLevelAvailability:          profileSettings.SeverityLevels.Availability,
+			v1alpha1.SeverityLevelCustom:                profileSettings.SeverityLevels.Custom,
+			v1alpha1.SeverityLevelError:                 profileSettings.SeverityLevels.Error,
+			v1alpha1.SeverityLevelMonitoringUnavailable: profileSettings.SeverityLevels.MonitoringUnavailable,
+			v1alpha1.SeverityLevelSlowdown:              profileSettings.SeverityLevels.Slowdown,
+			v1alpha1.SeverityLevelResource:              profileSettings.SeverityLevels.Resource,
+		},
+	}
+
+	tagFilterIncludeModes = enum[v1alpha1.TagFilterIncludeMode, profileSettings.TagFilterIncludeMode]{
+		name: "tag filter include mode",
+		values: map[v1alpha1.TagFilterIncludeMode]profileSettings.TagFilterIncludeMode{
+			v1alpha1.None:       profileSettings.TagFilterIncludeModes.None,
+			v1alpha1.IncludeAny: profileSettings.TagFilterIncludeModes.IncludeAny,
+			v1alpha1.IncludeAll: profileSettings.TagFilterIncludeModes.IncludeAll,
+		},
+	}
+
+	eventFilterTypes = enum[v1alpha1.EventFilterType, profileSettings.EventFilterType]{
+		name: "event filter type",
+		values: map[v1alpha1.EventFilterType]profileSettings.EventFilterType{
+			v1alpha1.EventFilterTypePredefined: profileSettings.EventFilterTypes.Predefined,
+			v1alpha1.EventFilterTypeCustom:     profileSettings.EventFilterTypes.Custom,
+		},
+	}
+
+	// Event types are generated from the alerting profile schema, which
+	// offers more of them than the upstream provider declares.
+	eventTypes = enum[v1alpha1.EventType, profileSettings.EventType]{
+		name:   "event type",
+		values: identity[v1alpha1.EventType, profileSettings.EventType](v1alpha1.EventTypes),
+	}
+
+	operators = enum[v1alpha1.Operator, profileSettings.Operator]{
+		name: "operator",
+		values: map[v1alpha1.Operator]profileSettings.Operator{
+			v1alpha1.OperatorBeginsWith:   profileSettings.Operators.BeginsWith,
+			v1alpha1.OperatorEndsWith:     profileSettings.Operators.EndsWith,
+			v1alpha1.OperatorContains:     profileSettings.Operators.Contains,
+			v1alpha1.OperatorRegexMatches: profileSettings.Operators.RegexMatches,
+			v1alpha1.OperatorStringEquals: profileSettings.Operators.StringEquals,
+		},
+	}
+)
+
+func crdToDto(p v1alpha1.ProfileParameters) (profileSettings.Profile, error) {
+	r := profileSettings.Profile{
+		Name:           p.Name,
+		ManagementZone: p.ManagementZone,
+	}
+
+	if p.SeverityRules != nil {
+		r.SeverityRules = make(profileSettings.SeverityRules, len(p.SeverityRules))
+	}
+	for i, in := range p.SeverityRules {
+		// Resolved tags that do not match the references, e.g. because they
+		// were set by a user or references were added since, are resolved
+		// again before the desired state is retried.
+		if len(in.ResolvedTags) != len(in.TagRefs) {
+			return profileSettings.Profile{}, generic.Unresolved(errors.Errorf(errFmtTagRefsUnresolved, i))
+		}
+		rule, err := convertSeverityRule(in)
+		if err != nil {
+			return profileSettings.Profile{}, err
+		}
+		r.SeverityRules[i] = rule
+	}
+
+	if p.EventFilters != nil {
+		r.EventFilters = make(profileSettings.EventFilters, len(p.EventFilters))
+	}
+	for i, in := range p.EventFilters {
+		if in.Type == v1alpha1.EventFilterTypePredefined && in.Predefined == nil {
+			return profileSettings.Profile{}, errors.Errorf(errFmtNoPredefinedFilter, i)
+		}
+		if in.Type == v1alpha1.EventFilterTypeCustom && in.Custom == nil {
+			return profileSettings.Profile{}, errors.Errorf(errFmtNoCustomFilter, i)
+		}
+		f, err := convertEventFilter(in)
+		if err != nil {
+			return profileSettings.Profile{}, err
+		}
+		r.EventFilters[i] = f
+	}
+
+	return r, nil
+}
+
+func convertSeverityRule(in v1alpha1.SeverityRule) (*profileSettings.SeverityRule, error) {
+	level, err := severityLevels.toDTO(in.SeverityLevel)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := tagFilterIncludeModes.toDTO(in.TagFilterIncludeMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &profileSettings.SeverityRule{
+		SeverityLevel:        level,
+		DelayInMinutes:       in.DelayInMinutes,
+		TagFilterIncludeMode: mode,
+		Tags:                 tagFilter(in),
+	}, nil
+}
+
+// tagFilter returns the tags of the supplied severity rule followed by the
+// tags its references resolved to that it does not list already.
+func tagFilter(in v1alpha1.SeverityRule) []string {
+	tags := clone(in.Tags)
+	listed := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		listed[t] = true
+	}
+	for _, t := range in.ResolvedTags {
+		if !listed[t] {
+			tags, listed[t] = append(tags, t), true
+		}
+	}
+	return tags
+}
+
+func convertEventFilter(in v1alpha1.EventFilter) (*profileSettings.EventFilter, error) {
+	t, err := eventFilterTypes.toDTO(in.Type)
+	if err != nil {
+		return nil, err
+	}
+	f := &profileSettings.EventFilter{Type: t}
+
+	if in.Predefined != nil {
+		et, err := eventTypes.toDTO(in.Predefined.EventType)
+		if err != nil {
+			return nil, err
+		}
+		f.Predefined = &profileSettings.PredefinedEventFilter{EventType: et, Negate: in.Predefined.Negate}
+	}
+
+	if in.Custom != nil {
+		f.Custom = &profileSettings.CustomEventFilter{}
+		if f.Custom.Title, err = convertTextFilter(in.Custom.Title); err != nil {
+			return nil, err
+		}
+		if f.Custom.Description, err = convertTextFilter(in.Custom.Description); err != nil {
+			return nil, err
+		}
+		if m := in.Custom.MetadataFilter; m != nil {
+			f.Custom.MetadataFilter = &profileSettings.MetadataFilter{}
+			if m.MetadataFilterItems != nil {
+				f.Custom.MetadataFilter.MetadataFilterItems = make(profileSettings.MetadataFilterItems, len(m.MetadataFilterItems))
+			}
+			for i, item := range m.MetadataFilterItems {
+				f.Custom.MetadataFilter.MetadataFilterItems[i] = &profileSettings.MetadataFilterItem{
+					MetadataKey:   item.MetadataKey,
+					MetadataValue: item.MetadataValue,
+				}
+			}
+		}
+	}
+
+	return f, nil
+}
+
+func convertTextFilter(in *v1alpha1.TextFilter) (*profileSettings.TextFilter, error) {
+	if in == nil {
+		return nil, nil
+	}
+	op, err := operators.toDTO(in.Operator)
+	if err != nil {
+		return nil, err
+	}
+	return &profileSettings.TextFilter{
+		Operator:      op,
+		Value:         in.Value,
+		Negate:        in.Negate,
+		Enabled:       in.Enabled,
+		CaseSensitive: in.CaseSensitive,
+	}, nil
+}
+
+// lateInitialize fills unset optional parameters from the observed profile.
+func lateInitialize(in *v1alpha1.ProfileParameters, p profileSettings.Profile) bool {
+	li := &lateinit.Tracker{}
+	lateinit.Ptr(li, &in.ManagementZone, p.ManagementZone)
+
+	// A profile that cannot be converted completely is reported by Observe.
+	// Late-initializing only part of its rules and filters would delete the
+	// others with the next update.
+	if observed, omitted := dtoToCrd(p); len(omitted) == 0 {
+		lateinit.Slice(li, &in.SeverityRules, observed.SeverityRules)
+		lateinit.Slice(li, &in.EventFilters, observed.EventFilters)
+	}
+
+	return li.Changed()
+}
+
+// dtoToCrd returns the parameters that desire the supplied profile and
+// describes the severity rules and event filters it omits because they use
+// values the Profile API does not support, e.g. ones Dynatrace added since.
+func dtoToCrd(p profileSettings.Profile) (v1alpha1.ProfileParameters, []string) {
+	r := v1alpha1.ProfileParameters{
+		Name:           p.Name,
+		ManagementZone: p.ManagementZone,
+	}
+	var omitted []string
+
+	if p.SeverityRules != nil {
+		r.SeverityRules = make([]v1alpha1.SeverityRule, 0, len(p.SeverityRules))
+	}
+	for i, in := range p.SeverityRules {
+		if in == nil {
+			continue
+		}
+		rule, err := dtoToSeverityRule(*in)
+		if err != nil {
+			omitted = append(omitted, fmt.Sprintf(msgFmtOmittedSeverityRule, i, err))
+			continue
+		}
+		r.SeverityRules = append(r.SeverityRules, rule)
+	}
+
+	if p.EventFilters != nil {
+		r.EventFilters = make([]v1alpha1.EventFilter, 0, len(p.EventFilters))
+	}
+	for i, in := range p.EventFilters {
+		if in == nil {
+			continue
+		}
+		f, err := dtoToEventFilter(*in)
+		if err != nil {
+			omitted = append(omitted, fmt.Sprintf(msgFmtOmittedEventFilter, i, err))
+			continue
+		}
+		r.EventFilters = append(r.EventFilters, f)
+	}
+
+	return r, omitted
+}
+
+func dtoToSeverityRule(in profileSettings.SeverityRule) (v1alpha1.SeverityRule, error) {
+	level, err := severityLevels.fromDTO(in.SeverityLevel)
+	if err != nil {
+		return v1alpha1.SeverityRule{}, err
+	}
+	mode, err := tagFilterIncludeModes.fromDTO(in.TagFilterIncludeMode)
+	if err != nil {
+		return v1alpha1.SeverityRule{}, err
+	}
+
+	return v1alpha1.SeverityRule{
+		SeverityLevel:        level,
+		DelayInMinutes:       in.DelayInMinutes,
+		TagFilterIncludeMode: mode,
+		Tags:                 clone(in.Tags),
+	}, nil
+}
+
+func dtoToEventFilter(in profileSettings.EventFilter) (v1alpha1.EventFilter, error) {
+	t, err := eventFilterTypes.fromDTO(in.Type)
+	if err != nil {
+		return v1alpha1.EventFilter{}, err
+	}
+	f := v1alpha1.EventFilter{Type: t}
+
+	if in.Predefined != nil {
+		et, err := eventTypes.fromDTO(in.Predefined.EventType)
+		if err != nil {
+			return v1alpha1.EventFilter{}, err
+		}
+		f.Predefined = &v1alpha1.PredefinedEventFilter{EventType: et, Negate: in.Predefined.Negate}
+	}
+
+	if in.Custom != nil {
+		f.Custom = &v1alpha1.CustomEventFilter{}
+		if f.Custom.Title, err = dtoToTextFilter(in.Custom.Title); err != nil {
+			return v1alpha1.EventFilter{}, err
+		}
+		if f.Custom.Description, err = dtoToTextFilter(in.Custom.Description); err != nil {
+			return v1alpha1.EventFilter{}, err
+		}
+		if m := in.Custom.MetadataFilter; m != nil {
+			f.Custom.MetadataFilter = &v1alpha1.MetadataFilter{}
+			if m.MetadataFilterItems != nil {
+				f.Custom.MetadataFilter.MetadataFilterItems = make([]v1alpha1.MetadataFilterItem, 0, len(m.MetadataFilterItems))
+			}
+			for _, item := range m.MetadataFilterItems {
+				if item == nil {
+					continue
+				}
+				f.Custom.MetadataFilter.MetadataFilterItems = append(f.Custom.MetadataFilter.MetadataFilterItems, v1alpha1.MetadataFilterItem{
+					MetadataKey:   item.MetadataKey,
+					MetadataValue: item.MetadataValue,
+				})
+			}
+		}
+	}
+
+	return f, nil
+}
+
+func dtoToTextFilter(in *profileSettings.TextFilter) (*v1alpha1.TextFilter, error) {
+	if in == nil {
+		return nil, nil
+	}
+	op, err := operators.fromDTO(in.Operator)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.TextFilter{
+		Operator:      op,
+		Value:         in.Value,
+		Negate:        in.Negate,
+		Enabled:       in.Enabled,
+		CaseSensitive: in.CaseSensitive,
+	}, nil
+}
+
+// clone returns a copy of the supplied slice, so that converted objects do
+// not share their lists with the objects they were converted from.
+func clone[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+// generateObservation returns the observed state of the supplied profile and
+// describes the severity rules and event filters it omits because they use
+// values the Profile API does not support, e.g. ones Dynatrace added since.
+func generateObservation(id string, o *settings20.Object, p profileSettings.Profile) (v1alpha1.ProfileObservation, []string) {
+	obs := v1alpha1.ProfileObservation{
+		Id:                        id,
+		SettingsObjectObservation: o.Observation(),
+		Name:                      p.Name,
+		ManagementZone:            p.ManagementZone,
+	}
+	var omitted []string
+
+	for i, in := range p.SeverityRules {
+		if in == nil {
+			continue
+		}
+		rule, err := dtoToSeverityRule(*in)
+		if err != nil {
+			omitted = append(omitted, fmt.Sprintf(msgFmtOmittedSeverityRule, i, err))
+			continue
+		}
+		obs.SeverityRules = append(obs.SeverityRules, v1alpha1.SeverityRuleObservation{
+			SeverityLevel:        rule.SeverityLevel,
+			DelayInMinutes:       rule.DelayInMinutes,
+			TagFilterIncludeMode: rule.TagFilterIncludeMode,
+			Tags:                 rule.Tags,
+		})
+	}
+
+	for i, in := range p.EventFilters {
+		if in == nil {
+			continue
+		}
+		f, err := dtoToEventFilter(*in)
+		if err != nil {
+			omitted = append(omitted, fmt.Sprintf(msgFmtOmittedEventFilter, i, err))
+			continue
+		}
+		obs.EventFilters = append(obs.EventFilters, f)
+	}
+
+	return obs, omitted
+}
+
+// observedCondition returns the Observed condition of a profile whose
+// observation omitted the supplied severity rules and event filters.
+func observedCondition(omitted []string) xpv1.Condition {
+	if len(omitted) == 0 {
+		return apisv1alpha1.ObservedCompletely()
+	}
+	return apisv1alpha1.ObservedPartially(strings.Join(omitted, "; "))
+}
@@ -0,0 +1,455 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	profileSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile/settings"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-dynatrace/apis/alerting/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+)
+
+// full returns parameters that set every field, so that conversions that
+// drop a field leave a zero value behind.
+func full() v1alpha1.ProfileParameters {
+	zone := "1234567890"
+	team := "team-a"
+	negate := true
+	text := func(op v1alpha1.Operator) *v1alpha1.TextFilter {
+		return &v1alpha1.TextFilter{Operator: op, Value: "value", Negate: true, Enabled: true, CaseSensitive: true}
+	}
+
+	return v1alpha1.ProfileParameters{
+		Name:           "cool-profile",
+		ManagementZone: &zone,
+		SeverityRules: []v1alpha1.SeverityRule{
+			{
+				SeverityLevel:        v1alpha1.SeverityLevelSlowdown,
+				DelayInMinutes:       5,
+				TagFilterIncludeMode: v1alpha1.IncludeAll,
+				Tags:                 []string{"env:prod"},
+				TagRefs:              []v1alpha1.TagReference{{Name: "owner", Value: &team}},
+				ResolvedTags:         []string{"owner:team-a"},
+			},
+		},
+		EventFilters: []v1alpha1.EventFilter{{
+			Type:       v1alpha1.EventFilterTypeCustom,
+			Predefined: &v1alpha1.PredefinedEventFilter{EventType: v1alpha1.EventTypeESXiVMImpactHostMemorySaturation, Negate: true},
+			Custom: &v1alpha1.CustomEventFilter{
+				Title:       text(v1alpha1.OperatorContains),
+				Description: text(v1alpha1.OperatorRegexMatches),
+				MetadataFilter: &v1alpha1.MetadataFilter{MetadataFilterItems: []v1alpha1.MetadataFilterItem{
+					{MetadataKey: "dt.cost", MetadataValue: "value", Negate: &negate},
+				}},
+			},
+		}},
+	}
+}
+
+// zeroFields returns the paths of the fields of the supplied value that are
+// unset. Fields that are not serialized are skipped.
+func zeroFields(v reflect.Value, path string) []string {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return []string{path}
+		}
+		return zeroFields(v.Elem(), path)
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return []string{path}
+		}
+		var zero []string
+		for i := 0; i < v.Len(); i++ {
+			zero = append(zero, zeroFields(v.Index(i), path)...)
+		}
+		return zero
+	case reflect.Struct:
+		var zero []string
+		for i := 0; i < v.NumField(); i++ {
+			name := jsonName(v.Type().Field(i))
+			if name == "-" {
+				continue
+			}
+			zero = append(zero, zeroFields(v.Field(i), strings.TrimPrefix(path+"."+name, "."))...)
+		}
+		return zero
+	default:
+		if v.IsZero() {
+			return []string{path}
+		}
+		return nil
+	}
+}
+
+// jsonName returns the name the supplied field is serialized as.
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// jsonNames returns the sorted names the fields of the supplied struct type
+// are serialized as.
+func jsonNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "-" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFields(t *testing.T) {
+	cases := map[string]struct {
+		params any
+		dto    any
+		// references are fields of the parameters that reference other
+		// managed resources rather than map to a property.
+		references map[string]bool
+		// deprecated are fields of the parameters that are ignored, as the
+		// schema has no property for them.
+		deprecated map[string]bool
+	}{
+		"Profile":               {params: v1alpha1.ProfileParameters{}, dto: profileSettings.Profile{}},
+		"SeverityRule":          {params: v1alpha1.SeverityRule{}, dto: profileSettings.SeverityRule{}, references: map[string]bool{"resolvedTagFilter": true, "tagFilterRefs": true}},
+		"EventFilter":           {params: v1alpha1.EventFilter{}, dto: profileSettings.EventFilter{}},
+		"PredefinedEventFilter": {params: v1alpha1.PredefinedEventFilter{}, dto: profileSettings.PredefinedEventFilter{}},
+		"CustomEventFilter":     {params: v1alpha1.CustomEventFilter{}, dto: profileSettings.CustomEventFilter{}},
+		"TextFilter":            {params: v1alpha1.TextFilter{}, dto: profileSettings.TextFilter{}},
+		"MetadataFilter":        {params: v1alpha1.MetadataFilter{}, dto: profileSettings.MetadataFilter{}},
+		"MetadataFilterItem":    {params: v1alpha1.MetadataFilterItem{}, dto: profileSettings.MetadataFilterItem{}, deprecated: map[string]bool{"negate": true}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			want := jsonNames(reflect.TypeOf(tc.dto))
+			var got []string
+			for _, name := range jsonNames(reflect.TypeOf(tc.params)) {
+				if !tc.references[name] && !tc.deprecated[name] {
+					got = append(got, name)
+				}
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\nThe parameters should have a field for every property of the alerting profile schema, and no others.\n-schema properties, +parameters:\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestConvertAllFields(t *testing.T) {
+	in := full()
+	if zero := zeroFields(reflect.ValueOf(in), ""); len(zero) > 0 {
+		t.Fatalf("full(): unset fields %v", zero)
+	}
+
+	dto, err := crdToDto(in)
+	if err != nil {
+		t.Fatalf("crdToDto(...): %v", err)
+	}
+	if zero := zeroFields(reflect.ValueOf(dto), ""); len(zero) > 0 {
+		t.Errorf("crdToDto(...): properties not converted: %v", zero)
+	}
+
+	// The tags references resolved to are observed like any other tag.
+	want := full()
+	want.SeverityRules[0].Tags = append(want.SeverityRules[0].Tags, want.SeverityRules[0].ResolvedTags...)
+	want.SeverityRules[0].TagRefs, want.SeverityRules[0].ResolvedTags = nil, nil
+	// Negate is deprecated and not converted.
+	want.EventFilters[0].Custom.MetadataFilter.MetadataFilterItems[0].Negate = nil
+
+	got, omitted := dtoToCrd(dto)
+	if len(omitted) > 0 {
+		t.Fatalf("dtoToCrd(...): omitted %v", omitted)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("dtoToCrd(crdToDto(...)): -want, +got:\n%s\n", diff)
+	}
+}
+
+// values returns the values of an enum of the alerting profile schema, which
+// are declared as the fields of a struct.
+func values[D ~string](enum any) []D {
+	v := reflect.ValueOf(enum)
+	out := make([]D, v.NumField())
+	for i := range out {
+		out[i] = v.Field(i).Interface().(D)
+	}
+	return out
+}
+
+// checkEnum checks that the supplied enum maps every value of the schema to
+// the value of the same name.
+func checkEnum[A, D ~string](t *testing.T, e enum[A, D], schema []D) {
+	t.Helper()
+
+	want := make(map[string]string, len(schema))
+	for _, d := range schema {
+		want[string(d)] = string(d)
+	}
+	got := make(map[string]string, len(e.values))
+	for a, d := range e.values {
+		got[string(a)] = string(d)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nThe %s enum should map every value of the schema to the value of the same name.\n-want, +got:\n%s\n", e.name, diff)
+	}
+}
+
+func TestEnums(t *testing.T) {
+	checkEnum(t, severityLevels, values[profileSettings.SeverityLevel](profileSettings.SeverityLevels))
+	checkEnum(t, tagFilterIncludeModes, values[profileSettings.TagFilterIncludeMode](profileSettings.TagFilterIncludeModes))
+	checkEnum(t, eventFilterTypes, values[profileSettings.EventFilterType](profileSettings.EventFilterTypes))
+	checkEnum(t, operators, values[profileSettings.Operator](profileSettings.Operators))
+}
+
+func TestCrdToDto(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.ProfileParameters
+		want   error
+	}{
+		"NilOptionals": {
+			reason: "A profile without optional parameters should be converted.",
+			in:     v1alpha1.ProfileParameters{Name: "cool-profile"},
+		},
+		"NilPredefinedFilter": {
+			reason: "An event filter of type PREDEFINED without a predefined filter should return an error.",
+			in: v1alpha1.ProfileParameters{
+				Name:         "cool-profile",
+				EventFilters: []v1alpha1.EventFilter{{Type: v1alpha1.EventFilterTypePredefined}},
+			},
+			want: errors.Errorf(errFmtNoPredefinedFilter, 0),
+		},
+		"NilCustomFilter": {
+			reason: "An event filter of type CUSTOM without a custom filter should return an error.",
+			in: v1alpha1.ProfileParameters{
+				Name: "cool-profile",
+				EventFilters: []v1alpha1.EventFilter{
+					{Type: v1alpha1.EventFilterTypePredefined, Predefined: &v1alpha1.PredefinedEventFilter{EventType: v1alpha1.EventTypeOSIHighCPU}},
+					{Type: v1alpha1.EventFilterTypeCustom},
+				},
+			},
+			want: errors.Errorf(errFmtNoCustomFilter, 1),
+		},
+		"UnsupportedValue": {
+			reason: "A value the alerting profile schema does not know should return an error rather than be sent.",
+			in: v1alpha1.ProfileParameters{
+				Name:          "cool-profile",
+				SeverityRules: []v1alpha1.SeverityRule{{SeverityLevel: "SOMETIMES"}},
+			},
+			want: errors.Errorf(errFmtUnsupportedValue, "severity level", "SOMETIMES"),
+		},
+		"UnresolvedTagRefs": {
+			reason: "A severity rule whose tag references were not resolved should return an error rather than drop the tags.",
+			in: v1alpha1.ProfileParameters{
+				Name: "cool-profile",
+				SeverityRules: []v1alpha1.SeverityRule{{
+					SeverityLevel:        v1alpha1.SeverityLevelAvailability,
+					TagFilterIncludeMode: v1alpha1.IncludeAny,
+					TagRefs:              []v1alpha1.TagReference{{Name: "owner"}},
+				}},
+			},
+			want: generic.Unresolved(errors.Errorf(errFmtTagRefsUnresolved, 0)),
+		},
+		"StaleResolvedTags": {
+			reason: "A severity rule whose resolved tags do not match its tag references, e.g. because a user set them, should be resolved again rather than sent.",
+			in: v1alpha1.ProfileParameters{
+				Name: "cool-profile",
+				SeverityRules: []v1alpha1.SeverityRule{{
+					SeverityLevel:        v1alpha1.SeverityLevelAvailability,
+					TagFilterIncludeMode: v1alpha1.IncludeAny,
+					TagRefs:              []v1alpha1.TagReference{{Name: "owner"}},
+					ResolvedTags:         []string{"owner", "tier"},
+				}},
+			},
+			want: generic.Unresolved(errors.Errorf(errFmtTagRefsUnresolved, 0)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := crdToDto(tc.in)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncrdToDto(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTagFilter(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.SeverityRule
+		want   []string
+	}{
+		"NoTags": {
+			reason: "A severity rule without tags should not filter by tags.",
+		},
+		"Merged": {
+			reason: "Resolved tags should follow the listed tags, unless they are listed already.",
+			in: v1alpha1.SeverityRule{
+				Tags:         []string{"env:prod", "owner:team-a"},
+				TagRefs:      []v1alpha1.TagReference{{Name: "owner"}, {Name: "tier"}},
+				ResolvedTags: []string{"owner:team-a", "tier"},
+			},
+			want: []string{"env:prod", "owner:team-a", "tier"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tagFilter(tc.in)); diff != "" {
+				t.Errorf("\n%s\ntagFilter(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDtoToCrd(t *testing.T) {
+	type want struct {
+		params  v1alpha1.ProfileParameters
+		omitted []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		in     profileSettings.Profile
+		want   want
+	}{
+		"NilElements": {
+			reason: "Unset elements of lists should be skipped.",
+			in: profileSettings.Profile{
+				Name:          "cool-profile",
+				SeverityRules: profileSettings.SeverityRules{nil},
+				EventFilters:  profileSettings.EventFilters{nil},
+			},
+			want: want{params: v1alpha1.ProfileParameters{
+				Name:          "cool-profile",
+				SeverityRules: []v1alpha1.SeverityRule{},
+				EventFilters:  []v1alpha1.EventFilter{},
+			}},
+		},
+		"UnsupportedValue": {
+			reason: "Event filters with a value the Profile API does not know should be omitted and described rather than fail the conversion.",
+			in: profileSettings.Profile{
+				Name: "cool-profile",
+				EventFilters: profileSettings.EventFilters{
+					{Type: profileSettings.EventFilterTypes.Predefined, Predefined: &profileSettings.PredefinedEventFilter{EventType: "SOMETHING_NEW"}},
+					{Type: profileSettings.EventFilterTypes.Predefined, Predefined: &profileSettings.PredefinedEventFilter{EventType: "OSI_HIGH_CPU"}},
+				},
+			},
+			want: want{
+				params: v1alpha1.ProfileParameters{
+					Name: "cool-profile",
+					EventFilters: []v1alpha1.EventFilter{
+						{Type: v1alpha1.EventFilterTypePredefined, Predefined: &v1alpha1.PredefinedEventFilter{EventType: v1alpha1.EventTypeOSIHighCPU}},
+					},
+				},
+				omitted: []string{`eventFilters[0]: unsupported event type "SOMETHING_NEW"`},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			params, omitted := dtoToCrd(tc.in)
+			if diff := cmp.Diff(tc.want.params, params); diff != "" {
+				t.Errorf("\n%s\ndtoToCrd(...): -want parameters, +got parameters:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.omitted, omitted); diff != "" {
+				t.Errorf("\n%s\ndtoToCrd(...): -want omitted, +got omitted:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGenerateObservation(t *testing.T) {
+	type want struct {
+		obs     v1alpha1.ProfileObservation
+		omitted []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		in     profileSettings.Profile
+		want   want
+	}{
+		"Supported": {
+			reason: "A profile using supported values only should be observed completely.",
+			in: profileSettings.Profile{
+				Name:          "cool-profile",
+				SeverityRules: profileSettings.SeverityRules{{SeverityLevel: profileSettings.SeverityLevels.Availability, TagFilterIncludeMode: profileSettings.TagFilterIncludeModes.IncludeAny, Tags: []string{"owner:team-a"}}},
+			},
+			want: want{obs: v1alpha1.ProfileObservation{
+				Id:            "some-id",
+				Name:          "cool-profile",
+				SeverityRules: []v1alpha1.SeverityRuleObservation{{SeverityLevel: v1alpha1.SeverityLevelAvailability, TagFilterIncludeMode: v1alpha1.IncludeAny, Tags: []string{"owner:team-a"}}},
+			}},
+		},
+		"UnsupportedValues": {
+			reason: "Severity rules and event filters using values the Profile API does not know should be omitted and described rather than fail the observation.",
+			in: profileSettings.Profile{
+				Name: "cool-profile",
+				SeverityRules: profileSettings.SeverityRules{
+					{SeverityLevel: "SOMETHING_SEVERE", TagFilterIncludeMode: profileSettings.TagFilterIncludeModes.None},
+					{SeverityLevel: profileSettings.SeverityLevels.Error, TagFilterIncludeMode: profileSettings.TagFilterIncludeModes.None},
+				},
+				EventFilters: profileSettings.EventFilters{{
+					Type:       profileSettings.EventFilterTypes.Predefined,
+					Predefined: &profileSettings.PredefinedEventFilter{EventType: "SOMETHING_NEW"},
+				}},
+			},
+			want: want{
+				obs: v1alpha1.ProfileObservation{
+					Id:            "some-id",
+					Name:          "cool-profile",
+					SeverityRules: []v1alpha1.SeverityRuleObservation{{SeverityLevel: v1alpha1.SeverityLevelError, TagFilterIncludeMode: v1alpha1.None}},
+				},
+				omitted: []string{
+					`severityRules[0]: unsupported severity level "SOMETHING_SEVERE"`,
+					`eventFilters[0]: unsupported event type "SOMETHING_NEW"`,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			obs, omitted := generateObservation("some-id", &settings20.Object{}, tc.in)
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("\n%s\ngenerateObservation(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.omitted, omitted); diff != "" {
+				t.Errorf("\n%s\ngenerateObservation(...): -want omitted, +got omitted:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
+	upstream "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings/services/settings20"
+
+	"github.com/crossplane/provider-dynatrace/internal/credentials"
+)
+
+// A lister lists the settings objects of a schema.
+type lister interface {
+	List() (api.Stubs, error)
+}
+
+// newNotificationLister returns a lister of the problem notifications of all
+// types.
+var newNotificationLister = func(data []byte) (lister, error) {
+	c, err := credentials.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return upstream.Service[*notifications.Notification](c, notifications.SchemaID, notifications.SchemaVersion), nil
+}
+
+// dependents returns the problem notifications that send the problems of the
+// alerting profile with the supplied ID. They stop working if the profile is
+// deleted, whether they are managed or not.
+func dependents(creds []byte, id string) ([]string, error) {
+	l, err := newNotificationLister(creds)
+	if err != nil {
+		return nil, err
+	}
+	return referencing(l, id)
+}
+
+func referencing(l lister, id string) ([]string, error) {
+	stubs, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []string
+	for _, s := range stubs {
+		n, ok := s.Value.(*notifications.Notification)
+		if !ok || n.ProfileID != id {
+			continue
+		}
+		deps = append(deps, fmt.Sprintf("%s notification %q (%s)", n.Type, s.Name, s.ID))
+	}
+	return deps, nil
+}
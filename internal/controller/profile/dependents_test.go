@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+type mockLister struct {
+	list func() (api.Stubs, error)
+}
+
+func (m mockLister) List() (api.Stubs, error) { return m.list() }
+
+func TestReferencing(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		deps []string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		l      lister
+		want   want
+	}{
+		"Referenced": {
+			reason: "Notifications of the profile should be returned, and those of other profiles should not.",
+			l: mockLister{list: func() (api.Stubs, error) {
+				return api.Stubs{
+					{ID: "email-id", Name: "cool-email", Value: &notifications.Notification{Type: notifications.Types.Email, ProfileID: "profile-id"}},
+					{ID: "other-slack-id", Name: "other-slack", Value: &notifications.Notification{Type: notifications.Types.Slack, ProfileID: "other-profile-id"}},
+					{ID: "slack-id", Name: "cool-slack", Value: &notifications.Notification{Type: notifications.Types.Slack, ProfileID: "profile-id"}},
+				}, nil
+			}},
+			want: want{deps: []string{
+				`EMAIL notification "cool-email" (email-id)`,
+				`SLACK notification "cool-slack" (slack-id)`,
+			}},
+		},
+		"Unreferenced": {
+			reason: "A profile no notification sends the problems of should have no dependents.",
+			l: mockLister{list: func() (api.Stubs, error) {
+				return api.Stubs{
+					{ID: "other-slack-id", Name: "other-slack", Value: &notifications.Notification{Type: notifications.Types.Slack, ProfileID: "other-profile-id"}},
+				}, nil
+			}},
+			want: want{},
+		},
+		"ListError": {
+			reason: "Errors listing notifications should be returned.",
+			l:      mockLister{list: func() (api.Stubs, error) { return nil, errBoom }},
+			want:   want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deps, err := referencing(tc.l, "profile-id")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nreferencing(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deps, deps); diff != "" {
+				t.Errorf("\n%s\nreferencing(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,246 +17,116 @@ limitations under the License.
 package profile
 
 import (
-	"context"
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/meta"
-	"github.com/crossplane/provider-dynatrace/internal/credentials"
 	profile "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile"
 	profileSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile/settings"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/json"
-	"net/http"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
-	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
-	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane/provider-dynatrace/apis/alerting/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
-	"github.com/crossplane/provider-dynatrace/internal/features"
-)
-
-const (
-	errNotProfile   = "managed resource is not a Profile custom resource"
-	errTrackPCUsage = "cannot track ProviderConfig usage"
-	errGetPC        = "cannot get ProviderConfig"
-	errGetCreds     = "cannot get credentials"
-
-	errNewClient   = "cannot create new Service"
-	errCredentials = "cannot unmarshal credentials"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+	"github.com/crossplane/provider-dynatrace/internal/credentials"
+	"github.com/crossplane/provider-dynatrace/internal/drift"
 )
 
 var (
-	newProfileService = func(data []byte) (settings.CRUDService[*profileSettings.Profile], error) {
+	newProfileService = func(data []byte) (settings20.CRUDService[*profileSettings.Profile], error) {
 		c, err := credentials.Unmarshal(data)
 		if err != nil {
 			return nil, err
 		}
 
-		return profile.Service(c), nil
+		return settings20.NewService[*profileSettings.Profile](profile.SchemaID, settings20.NewClient(c)), nil
 	}
 )
 
+// kind maps Profile managed resources to alerting profile settings objects.
+var kind = &generic.Kind[*v1alpha1.Profile, profileSettings.Profile]{
+	GroupKind:        v1alpha1.ProfileGroupKind,
+	GroupVersionKind: v1alpha1.ProfileGroupVersionKind,
+	Name:             func(cr *v1alpha1.Profile) string { return cr.Spec.ForProvider.Name },
+	ToDTO:            func(cr *v1alpha1.Profile) (profileSettings.Profile, error) { return crdToDto(cr.Spec.ForProvider) },
+	LateInitialize: func(cr *v1alpha1.Profile, p profileSettings.Profile) bool {
+		return lateInitialize(&cr.Spec.ForProvider, p)
+	},
+	Observe: func(cr *v1alpha1.Profile, id string, o *settings20.Object, p profileSettings.Profile) error {
+		obs, omitted := generateObservation(id, o, p)
+		cr.Status.AtProvider = obs
+		cr.SetConditions(observedCondition(omitted))
+		return nil
+	},
+	Validate: func(cr *v1alpha1.Profile) field.ErrorList {
+		return validate(cr.Spec.ForProvider)
+	},
+	Warnings: func(cr *v1alpha1.Profile) []string {
+		return warnings(cr.Spec.ForProvider)
+	},
+	Spec: func(cr *v1alpha1.Profile) *apisv1alpha1.SettingsObjectSpec {
+		return &cr.Spec.SettingsObjectSpec
+	},
+	Metadata: func(cr *v1alpha1.Profile) *apisv1alpha1.SettingsObjectObservation {
+		return &cr.Status.AtProvider.SettingsObjectObservation
+	},
+	// Severity rules and event filters with values the Profile API does not
+	// support are omitted from the export and must be added by hand.
+	FromDTO: func(p profileSettings.Profile) (*v1alpha1.Profile, []string, error) {
+		params, omitted := dtoToCrd(p)
+		incomplete := make([]string, len(omitted))
+		for i, o := range omitted {
+			incomplete[i] = "spec.forProvider." + o
+		}
+		return &v1alpha1.Profile{Spec: v1alpha1.ProfileSpec{ForProvider: params}}, incomplete, nil
+	},
+	// Notifications stop sending problems if their alerting profile is
+	// deleted.
+	Dependents: dependents,
+	// Severity rules, their tag filters, event filters and metadata filter
+	// items are sets in the alerting profile schema.
+	DiffOptions: []cmp.Option{
+		cmpopts.IgnoreFields(profileSettings.Profile{}, "LegacyID"),
+		drift.Unordered[*profileSettings.SeverityRule](),
+		drift.Unordered[string](),
+		drift.Unordered[*profileSettings.EventFilter](),
+		drift.Unordered[*profileSettings.MetadataFilterItem](),
+	},
+}
+
 // Setup adds a controller that reconciles Profile managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
-	name := managed.ControllerName(v1alpha1.ProfileGroupKind)
-
-	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
-	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
-		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
-	}
-
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.ProfileGroupVersionKind),
-		managed.WithExternalConnecter(&connector{
-			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newProfileService}),
-		managed.WithLogger(o.Logger.WithValues("controller", name)),
-		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithConnectionPublishers(cps...))
-
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.Profile{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+	return generic.Setup(mgr, o, kind, newProfileService)
 }
 
-// A connector is expected to produce an ExternalClient when its Connect method
-// is called.
-type connector struct {
-	kube         client.Client
-	usage        resource.Tracker
-	newServiceFn func(creds []byte) (settings.CRUDService[*profileSettings.Profile], error)
+// Exporter returns an Exporter of alerting profiles.
+func Exporter() generic.Exporter {
+	return generic.NewExporter(kind, newProfileService)
 }
 
-// Connect typically produces an ExternalClient by:
-// 1. Tracking that the managed resource is using a ProviderConfig.
-// 2. Getting the managed resource's ProviderConfig.
-// 3. Getting the credentials specified by the ProviderConfig.
-// 4. Using the credentials to form a client.
-func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*v1alpha1.Profile)
-	if !ok {
-		return nil, errors.New(errNotProfile)
-	}
-
-	if err := c.usage.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackPCUsage)
+// Ref returns a reference to the exported Profile that desires the alerting
+// profile with the supplied ID instead of the ID, unless no Profile was
+// exported for it.
+func Ref(id *string, name generic.NameFunc) (*string, *xpv1.Reference) {
+	if id == nil {
+		return nil, nil
 	}
-
-	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetPC)
-	}
-
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
-	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
-	}
-
-	svc, err := c.newServiceFn(data)
-	if err != nil {
-		return nil, errors.Wrap(err, errNewClient)
-	}
-
-	return &external{service: svc}, nil
-}
-
-// An ExternalClient observes, then either creates, updates, or deletes an
-// external resource to ensure it reflects the managed resource's desired state.
-type external struct {
-	// A 'client' used to connect to the external resource API. In practice this
-	// would be something like an AWS SDK client.
-	service settings.CRUDService[*profileSettings.Profile]
-}
-
-func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.Profile)
+	n, ok := name(v1alpha1.ProfileGroupKind, *id)
 	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotProfile)
-	}
-
-	id := meta.GetExternalName(cr)
-
-	var p profileSettings.Profile
-	err := c.service.Get(id, &p)
-	if err != nil {
-		var restError rest.Error
-		if errors.As(err, &restError) {
-			if restError.Code == http.StatusNotFound {
-				return managed.ExternalObservation{ResourceExists: false}, nil
-			}
-		}
-
-		return managed.ExternalObservation{}, err
+		return id, nil
 	}
-
-	// object exists -> check if updated
-	cr.Status.SetConditions(xpv1.Available())
-	cr.Status.AtProvider.Id = id
-
-	local, err := crdToDto(cr.Spec.ForProvider)
-	if err != nil {
-		return managed.ExternalObservation{}, err
-	}
-
-	if diff := cmp.Diff(p, local, cmpopts.IgnoreFields(profileSettings.Profile{}, "LegacyID"), cmpopts.EquateEmpty()); diff != "" {
-		cr.SetConditions(xpv1.Unavailable())
-		return managed.ExternalObservation{
-			ResourceExists:   true,
-			ResourceUpToDate: false,
-			Diff:             diff,
-		}, nil
-	}
-
-	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
-	}, nil
+	return nil, &xpv1.Reference{Name: n}
 }
 
-func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.Profile)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotProfile)
-	}
-
-	p, err := crdToDto(cr.Spec.ForProvider)
-	if err != nil {
-		return managed.ExternalCreation{}, err
-	}
-	createResp, err := c.service.Create(&p)
-	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, "failed to create")
-	}
-
-	meta.SetExternalName(cr, createResp.ID)
-	cr.Status.SetConditions(xpv1.Creating())
-
-	return managed.ExternalCreation{}, nil
+// Planner returns a Planner of alerting profiles.
+func Planner() generic.Planner {
+	return generic.NewPlanner(kind, newProfileService)
 }
 
-func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*v1alpha1.Profile)
-	if !ok {
-		return managed.ExternalUpdate{}, errors.New(errNotProfile)
-	}
-
-	dto, err := crdToDto(cr.Spec.ForProvider)
-	if err != nil {
-		return managed.ExternalUpdate{}, err
-	}
-
-	err = c.service.Update(meta.GetExternalName(cr), &dto)
-	if err != nil {
-		return managed.ExternalUpdate{}, err
-	}
-
-	return managed.ExternalUpdate{}, nil
-}
-
-func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.Profile)
-	if !ok {
-		return errors.New(errNotProfile)
-	}
-
-	err := c.service.Delete(meta.GetExternalName(cr))
-	if err != nil {
-		return err
-	}
-	cr.SetConditions(xpv1.Deleting())
-
-	return nil
-}
-
-func crdToDto(p v1alpha1.ProfileParameters) (profileSettings.Profile, error) {
-
-	j, err := json.Marshal(p)
-	if err != nil {
-		return profileSettings.Profile{}, err
-	}
-
-	var r profileSettings.Profile
-	err = json.Unmarshal(j, &r)
-	if err != nil {
-		return profileSettings.Profile{}, err
-	}
-
-	return r, nil
+// SetupWebhook adds a webhook that validates Profile managed resources.
+func SetupWebhook(mgr ctrl.Manager) error {
+	return generic.SetupWebhook(mgr, kind)
 }
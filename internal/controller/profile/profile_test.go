@@ -18,15 +18,29 @@ package profile
 
 import (
 	"context"
-	profileSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile/settings"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
+	"net/http"
 	"testing"
 
-	"github.com/google/go-cmp/cmp"
-
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile"
+	profileSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile/settings"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-dynatrace/apis/alerting/v1alpha1"
+	tagsv1alpha1 "github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20/fake"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic/generictest"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -37,14 +51,60 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
+// profileParams returns the parameters of the Profile managed resources in
+// these tests.
+func profileParams() v1alpha1.ProfileParameters {
+	return v1alpha1.ProfileParameters{
+		Name: "cool-profile",
+		SeverityRules: []v1alpha1.SeverityRule{
+			{SeverityLevel: v1alpha1.SeverityLevelAvailability, TagFilterIncludeMode: v1alpha1.IncludeAny, Tags: []string{"env:prod"}},
+		},
+		EventFilters: []v1alpha1.EventFilter{
+			{Type: v1alpha1.EventFilterTypePredefined, Predefined: &v1alpha1.PredefinedEventFilter{EventType: v1alpha1.EventTypeOSIHighCPU}},
+		},
+	}
+}
+
+func alertingProfile(id string) *v1alpha1.Profile {
+	cr := &v1alpha1.Profile{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		Spec:       v1alpha1.ProfileSpec{ForProvider: profileParams()},
+	}
+	if id != "" {
+		meta.SetExternalName(cr, id)
+	}
+	return cr
+}
+
+func desired(t *testing.T) profileSettings.Profile {
+	t.Helper()
+	p, err := crdToDto(profileParams())
+	if err != nil {
+		t.Fatalf("crdToDto(...): %v", err)
+	}
+	return p
+}
+
 func TestObserve(t *testing.T) {
-	type fields struct {
-		service settings.CRUDService[*profileSettings.Profile]
+	api := fake.NewServer()
+	defer api.Close()
+
+	svc, err := newProfileService(api.Credentials())
+	if err != nil {
+		t.Fatalf("newProfileService(...): %v", err)
 	}
 
+	upToDate := api.Create(profile.SchemaID, fake.ScopeEnvironment, desired(t))
+	renamed := desired(t)
+	renamed.Name = "renamed-profile"
+	drifted := api.Create(profile.SchemaID, fake.ScopeEnvironment, renamed)
+	unsupported := desired(t)
+	unsupported.EventFilters[0].Predefined.EventType = "SOMETHING_NEW"
+	newer := api.Create(profile.SchemaID, fake.ScopeEnvironment, unsupported)
+
 	type args struct {
-		ctx context.Context
-		mg  resource.Managed
+		fault *fake.Fault
+		mg    resource.Managed
 	}
 
 	type want struct {
@@ -54,23 +114,181 @@ func TestObserve(t *testing.T) {
 
 	cases := map[string]struct {
 		reason string
-		fields fields
 		args   args
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NotFound": {
+			reason: "A profile that does not exist should be reported as such.",
+			args:   args{mg: alertingProfile("missing")},
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"UpToDate": {
+			reason: "A profile that matches the desired state should be up to date.",
+			args:   args{mg: alertingProfile(upToDate)},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}},
+		},
+		"Drifted": {
+			reason: "A profile that differs from the desired state should not be up to date.",
+			args:   args{mg: alertingProfile(drifted)},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}},
+		},
+		"UnsupportedValue": {
+			reason: "A profile using a value the Profile API does not know should be observed rather than fail, so that it can be updated or deleted.",
+			args:   args{mg: alertingProfile(newer)},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}},
+		},
+		"Throttled": {
+			reason: "Errors reading the profile should be returned.",
+			args: args{
+				fault: &fake.Fault{Method: http.MethodGet, Code: http.StatusTooManyRequests},
+				mg:    alertingProfile(upToDate),
+			},
+			want: want{err: rest.Error{Code: http.StatusTooManyRequests, Message: http.StatusText(http.StatusTooManyRequests)}},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{service: tc.fields.service}
-			got, err := e.Observe(tc.args.ctx, tc.args.mg)
+			if tc.args.fault != nil {
+				api.Inject(*tc.args.fault)
+			}
+			e := generic.NewExternal(kind, svc)
+			got, err := e.Observe(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+			if diff := cmp.Diff(tc.want.o, got, cmpopts.IgnoreFields(managed.ExternalObservation{}, "Diff")); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
 		})
 	}
 }
+
+func TestReconcile(t *testing.T) {
+	env := generictest.New(t, kind, newProfileService)
+	cr := alertingProfile("")
+	env.Create(t, cr)
+
+	// The profile is created and its ID recorded.
+	env.Reconcile(t, cr)
+	id := meta.GetExternalName(cr)
+	checkValue(t, env, id)
+
+	// The profile becomes ready once it is observed.
+	env.Reconcile(t, cr)
+	generictest.CheckCondition(t, cr, xpv1.Available())
+	generictest.CheckCondition(t, cr, xpv1.ReconcileSuccess())
+	if got := cr.Status.AtProvider.ModifiedBy; got != fake.Author {
+		t.Errorf("Reconcile(...): status.atProvider.modifiedBy: want %q, got %q", fake.Author, got)
+	}
+
+	// Changes made by others are reverted.
+	renamed := desired(t)
+	renamed.Name = "renamed-profile"
+	env.API.Update(id, renamed)
+	env.Reconcile(t, cr)
+	checkValue(t, env, id)
+
+	// The profile is not deleted while a notification sends its problems.
+	notification := env.API.Create(notifications.SchemaID, fake.ScopeEnvironment, &notifications.Notification{
+		Name:      "cool-notification",
+		Type:      notifications.Types.Email,
+		ProfileID: id,
+	})
+	env.Delete(t, cr)
+	env.Reconcile(t, cr)
+	if _, ok := env.API.Object(id); !ok {
+		t.Fatalf("Reconcile(...): profile referenced by a notification was deleted")
+	}
+	if got := cr.GetCondition(apisv1alpha1.TypeDeletionBlocked); got.Reason != apisv1alpha1.ReasonHasDependents {
+		t.Errorf("Reconcile(...): want DeletionBlocked condition with reason %q, got %+v", apisv1alpha1.ReasonHasDependents, got)
+	}
+
+	// Once the notification is gone, so are the profile and its managed
+	// resource.
+	env.API.Delete(notification)
+	env.Reconcile(t, cr)
+	if _, ok := env.API.Object(id); ok {
+		t.Fatalf("Reconcile(...): profile was not deleted")
+	}
+	if env.Reconcile(t, cr) {
+		t.Errorf("Reconcile(...): managed resource of deleted profile still exists")
+	}
+}
+
+func TestReconcileTagReferences(t *testing.T) {
+	env := generictest.New(t, kind, newProfileService)
+
+	tag := &tagsv1alpha1.AutoTag{ObjectMeta: metav1.ObjectMeta{Name: "owner"}}
+	if err := env.Kube.Create(context.Background(), tag); err != nil {
+		t.Fatalf("Create(%s): %v", tag.GetName(), err)
+	}
+
+	team := "team-a"
+	cr := alertingProfile("")
+	cr.Spec.ForProvider.SeverityRules[0].TagRefs = []v1alpha1.TagReference{{Name: "owner", Value: &team}}
+	env.Create(t, cr)
+
+	// The profile is not created while the referenced AutoTag is not ready.
+	env.Reconcile(t, cr)
+	if got := env.API.Objects(profile.SchemaID); len(got) != 0 {
+		t.Fatalf("Reconcile(...): profile referencing an AutoTag that is not ready was created")
+	}
+	generictest.CheckCondition(t, cr, xpv1.Unavailable())
+	if got := cr.GetCondition(xpv1.TypeSynced); got.Status != corev1.ConditionFalse {
+		t.Errorf("Reconcile(...): want Synced condition False, got %+v", got)
+	}
+
+	// Once it is ready, the profile filters by its tag.
+	setAutoTagName(t, env, tag, "Owner")
+	env.Reconcile(t, cr)
+	id := meta.GetExternalName(cr)
+	checkTags(t, env, id, []string{"env:prod", "Owner:team-a"})
+
+	// Renaming the AutoTag updates the tag filter.
+	setAutoTagName(t, env, tag, "Team")
+	env.Reconcile(t, cr)
+	checkTags(t, env, id, []string{"env:prod", "Team:team-a"})
+}
+
+// setAutoTagName makes the supplied AutoTag ready with the supplied observed
+// name.
+func setAutoTagName(t *testing.T, env *generictest.Environment, tag *tagsv1alpha1.AutoTag, name string) {
+	t.Helper()
+	tag.SetConditions(xpv1.Available())
+	tag.Status.AtProvider.Name = name
+	if err := env.Kube.Update(context.Background(), tag); err != nil {
+		t.Fatalf("Update(%s): %v", tag.GetName(), err)
+	}
+}
+
+// checkTags checks the tag filter of the severity rule of the profile with
+// the supplied ID.
+func checkTags(t *testing.T, env *generictest.Environment, id string, want []string) {
+	t.Helper()
+	got := profileSettings.Profile{}
+	env.Value(t, id, &got)
+	if len(got.SeverityRules) != 1 {
+		t.Fatalf("Reconcile(...): want 1 severity rule, got %d", len(got.SeverityRules))
+	}
+	if diff := cmp.Diff(want, got.SeverityRules[0].Tags); diff != "" {
+		t.Errorf("Reconcile(...): -want tag filter, +got:\n%s\n", diff)
+	}
+}
+
+// checkValue checks that the value of the settings object with the supplied
+// ID is the desired profile.
+func checkValue(t *testing.T, env *generictest.Environment, id string) {
+	t.Helper()
+	got := profileSettings.Profile{}
+	env.Value(t, id, &got)
+	if diff := cmp.Diff(desired(t), got); diff != "" {
+		t.Errorf("Reconcile(...): -want profile, +got:\n%s\n", diff)
+	}
+}
+
+func reverse[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/provider-dynatrace/apis/alerting/v1alpha1"
+)
+
+const (
+	maxSeverityRules   = 100
+	maxEventFilters    = 100
+	maxTags            = 100
+	maxMetadataFilters = 20
+
+	minDelayInMinutes = 0
+	maxDelayInMinutes = 10000
+)
+
+var managementZoneID = regexp.MustCompile(`^-?\d{1,19}$`)
+
+// validate returns the constraints of the alerting profile schema the
+// supplied parameters violate.
+func validate(in v1alpha1.ProfileParameters) field.ErrorList {
+	p := field.NewPath("spec", "forProvider")
+	errs := field.ErrorList{}
+
+	if strings.TrimSpace(in.Name) == "" {
+		errs = append(errs, field.Required(p.Child("name"), "the name of the profile must not be blank"))
+	}
+	if in.ManagementZone != nil && !managementZoneID.MatchString(*in.ManagementZone) {
+		errs = append(errs, field.Invalid(p.Child("managementZone"), *in.ManagementZone, "must be the numeric ID of a management zone"))
+	}
+
+	if len(in.SeverityRules) > maxSeverityRules {
+		errs = append(errs, field.TooMany(p.Child("severityRules"), len(in.SeverityRules), maxSeverityRules))
+	}
+	for i, r := range in.SeverityRules {
+		errs = append(errs, validateSeverityRule(p.Child("severityRules").Index(i), r)...)
+	}
+
+	if len(in.EventFilters) > maxEventFilters {
+		errs = append(errs, field.TooMany(p.Child("eventFilters"), len(in.EventFilters), maxEventFilters))
+	}
+	for i, f := range in.EventFilters {
+		errs = append(errs, validateEventFilter(p.Child("eventFilters").Index(i), f)...)
+	}
+
+	return errs
+}
+
+func validateSeverityRule(p *field.Path, r v1alpha1.SeverityRule) field.ErrorList {
+	errs := field.ErrorList{}
+
+	if r.DelayInMinutes < minDelayInMinutes || r.DelayInMinutes > maxDelayInMinutes {
+		errs = append(errs, field.Invalid(p.Child("delayInMinutes"), r.DelayInMinutes, "must be between 0 and 10000"))
+	}
+
+	// Every tag reference resolves to one tag of the filter.
+	tags := len(r.Tags) + len(r.TagRefs)
+
+	switch r.TagFilterIncludeMode {
+	case v1alpha1.IncludeAny, v1alpha1.IncludeAll:
+		if tags == 0 {
+			errs = append(errs, field.Required(p.Child("tagFilter"), "tags or tag references must be specified unless tagFilterIncludeMode is NONE"))
+		}
+		if tags > maxTags {
+			errs = append(errs, field.TooMany(p.Child("tagFilter"), tags, maxTags))
+		}
+	case v1alpha1.None:
+		if len(r.Tags) > 0 {
+			errs = append(errs, field.Forbidden(p.Child("tagFilter"), "tags must not be specified if tagFilterIncludeMode is NONE"))
+		}
+		if len(r.TagRefs) > 0 {
+			errs = append(errs, field.Forbidden(p.Child("tagFilterRefs"), "tag references must not be specified if tagFilterIncludeMode is NONE"))
+		}
+	}
+
+	return errs
+}
+
+func validateEventFilter(p *field.Path, f v1alpha1.EventFilter) field.ErrorList {
+	errs := field.ErrorList{}
+
+	switch f.Type {
+	case v1alpha1.EventFilterTypePredefined:
+		if f.Predefined == nil {
+			errs = append(errs, field.Required(p.Child("predefinedFilter"), "event filters of type PREDEFINED must specify a predefined filter"))
+		}
+		if f.Custom != nil {
+			errs = append(errs, field.Forbidden(p.Child("customFilter"), "only event filters of type CUSTOM may specify a custom filter"))
+		}
+	case v1alpha1.EventFilterTypeCustom:
+		if f.Custom == nil {
+			errs = append(errs, field.Required(p.Child("customFilter"), "event filters of type CUSTOM must specify a custom filter"))
+		}
+		if f.Predefined != nil {
+			errs = append(errs, field.Forbidden(p.Child("predefinedFilter"), "only event filters of type PREDEFINED may specify a predefined filter"))
+		}
+	}
+
+	if f.Custom != nil && f.Custom.MetadataFilter != nil {
+		errs = append(errs, validateMetadataFilter(p.Child("customFilter", "metadataFilter", "metadataFilterItems"), f.Custom.MetadataFilter.MetadataFilterItems)...)
+	}
+
+	return errs
+}
+
+func validateMetadataFilter(p *field.Path, items []v1alpha1.MetadataFilterItem) field.ErrorList {
+	errs := field.ErrorList{}
+
+	if len(items) == 0 {
+		errs = append(errs, field.Required(p, "metadata filters must have at least one item"))
+	}
+	if len(items) > maxMetadataFilters {
+		errs = append(errs, field.TooMany(p, len(items), maxMetadataFilters))
+	}
+
+	keys := map[string]bool{}
+	for i, item := range items {
+		if keys[item.MetadataKey] {
+			errs = append(errs, field.Duplicate(p.Index(i).Child("metadataKey"), item.MetadataKey))
+		}
+		keys[item.MetadataKey] = true
+	}
+
+	return errs
+}
+
+// warnings returns warnings about deprecated parameters the supplied
+// parameters set.
+func warnings(in v1alpha1.ProfileParameters) []string {
+	var w []string
+	for i, f := range in.EventFilters {
+		if f.Custom == nil || f.Custom.MetadataFilter == nil {
+			continue
+		}
+		for j, item := range f.Custom.MetadataFilter.MetadataFilterItems {
+			if item.Negate == nil {
+				continue
+			}
+			p := field.NewPath("spec", "forProvider", "eventFilters").Index(i).Child("customFilter", "metadataFilter", "metadataFilterItems").Index(j).Child("negate")
+			w = append(w, p.String()+" is deprecated and ignored: the alerting profile schema does not support negating metadata filters")
+		}
+	}
+	return w
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/provider-dynatrace/apis/alerting/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	p := field.NewPath("spec", "forProvider")
+
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.ProfileParameters
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "A profile with consistent severity rules and event filters should be valid.",
+			in: v1alpha1.ProfileParameters{
+				Name: "cool-profile",
+				SeverityRules: []v1alpha1.SeverityRule{
+					{SeverityLevel: v1alpha1.SeverityLevelAvailability, DelayInMinutes: 5, TagFilterIncludeMode: v1alpha1.IncludeAny, Tags: []string{"env:prod"}},
+				},
+				EventFilters: []v1alpha1.EventFilter{
+					{Type: v1alpha1.EventFilterTypePredefined, Predefined: &v1alpha1.PredefinedEventFilter{EventType: v1alpha1.EventTypeOSIHighCPU}},
+				},
+			},
+			want: field.ErrorList{},
+		},
+		"TooManySeverityRules": {
+			reason: "At most 100 severity rules should be allowed.",
+			in: v1alpha1.ProfileParameters{
+				Name:          "cool-profile",
+				SeverityRules: make([]v1alpha1.SeverityRule, 101),
+			},
+			want: field.ErrorList{field.TooMany(p.Child("severityRules"), 101, 100)},
+		},
+		"InvalidSeverityRule": {
+			reason: "The delay should be between 0 and 10000 minutes and tags should match the include mode.",
+			in: v1alpha1.ProfileParameters{
+				Name: "cool-profile",
+				SeverityRules: []v1alpha1.SeverityRule{
+					{DelayInMinutes: 10001, TagFilterIncludeMode: v1alpha1.IncludeAll},
+					{DelayInMinutes: -1, TagFilterIncludeMode: v1alpha1.None, Tags: []string{"env:prod"}},
+				},
+			},
+			want: field.ErrorList{
+				field.Invalid(p.Child("severityRules").Index(0).Child("delayInMinutes"), int32(10001), "must be between 0 and 10000"),
+				field.Required(p.Child("severityRules").Index(0).Child("tagFilter"), "tags or tag references must be specified unless tagFilterIncludeMode is NONE"),
+				field.Invalid(p.Child("severityRules").Index(1).Child("delayInMinutes"), int32(-1), "must be between 0 and 10000"),
+				field.Forbidden(p.Child("severityRules").Index(1).Child("tagFilter"), "tags must not be specified if tagFilterIncludeMode is NONE"),
+			},
+		},
+		"TagReferencesOnly": {
+			reason: "A severity rule should be allowed to filter only by referenced tags.",
+			in: v1alpha1.ProfileParameters{
+				Name: "cool-profile",
+				SeverityRules: []v1alpha1.SeverityRule{
+					{TagFilterIncludeMode: v1alpha1.IncludeAny, TagRefs: []v1alpha1.TagReference{{Name: "owner"}}},
+				},
+			},
+			want: field.ErrorList{},
+		},
+		"TagReferencesWithoutFilter": {
+			reason: "Tag references should not be allowed if the include mode is NONE.",
+			in: v1alpha1.ProfileParameters{
+				Name: "cool-profile",
+				SeverityRules: []v1alpha1.SeverityRule{
+					{TagFilterIncludeMode: v1alpha1.None, TagRefs: []v1alpha1.TagReference{{Name: "owner"}}},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(p.Child("severityRules").Index(0).Child("tagFilterRefs"), "tag references must not be specified if tagFilterIncludeMode is NONE"),
+			},
+		},
+		"TooManyTags": {
+			reason: "Tags and tag references should count towards the same limit.",
+			in: v1alpha1.ProfileParameters{
+				Name: "cool-profile",
+				SeverityRules: []v1alpha1.SeverityRule{
+					{TagFilterIncludeMode: v1alpha1.IncludeAll, Tags: make([]string, 100), TagRefs: []v1alpha1.TagReference{{Name: "owner"}}},
+				},
+			},
+			want: field.ErrorList{
+				field.TooMany(p.Child("severityRules").Index(0).Child("tagFilter"), 101, 100),
+			},
+		},
+		"InconsistentEventFilters": {
+			reason: "Event filters should only specify the filter matching their type.",
+			in: v1alpha1.ProfileParameters{
+				Name: "cool-profile",
+				EventFilters: []v1alpha1.EventFilter{
+					{Type: v1alpha1.EventFilterTypePredefined, Custom: &v1alpha1.CustomEventFilter{}},
+					{Type: v1alpha1.EventFilterTypeCustom, Custom: &v1alpha1.CustomEventFilter{MetadataFilter: &v1alpha1.MetadataFilter{
+						MetadataFilterItems: []v1alpha1.MetadataFilterItem{{MetadataKey: "dt.cost"}, {MetadataKey: "dt.cost"}},
+					}}},
+				},
+			},
+			want: field.ErrorList{
+				field.Required(p.Child("eventFilters").Index(0).Child("predefinedFilter"), "event filters of type PREDEFINED must specify a predefined filter"),
+				field.Forbidden(p.Child("eventFilters").Index(0).Child("customFilter"), "only event filters of type CUSTOM may specify a custom filter"),
+				field.Duplicate(p.Child("eventFilters").Index(1).Child("customFilter", "metadataFilter", "metadataFilterItems").Index(1).Child("metadataKey"), "dt.cost"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := validate(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	negate := true
+
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.ProfileParameters
+		want   []string
+	}{
+		"NoDeprecatedParameters": {
+			reason: "Parameters that set no deprecated fields should not be warned about.",
+			in: v1alpha1.ProfileParameters{
+				EventFilters: []v1alpha1.EventFilter{
+					{Type: v1alpha1.EventFilterTypeCustom, Custom: &v1alpha1.CustomEventFilter{MetadataFilter: &v1alpha1.MetadataFilter{
+						MetadataFilterItems: []v1alpha1.MetadataFilterItem{{MetadataKey: "dt.cost", MetadataValue: "value"}},
+					}}},
+				},
+			},
+		},
+		"Negate": {
+			reason: "Negating a metadata filter item should be warned about, as it is ignored.",
+			in: v1alpha1.ProfileParameters{
+				EventFilters: []v1alpha1.EventFilter{
+					{Type: v1alpha1.EventFilterTypePredefined},
+					{Type: v1alpha1.EventFilterTypeCustom, Custom: &v1alpha1.CustomEventFilter{MetadataFilter: &v1alpha1.MetadataFilter{
+						MetadataFilterItems: []v1alpha1.MetadataFilterItem{
+							{MetadataKey: "dt.cost", MetadataValue: "value"},
+							{MetadataKey: "dt.owner", MetadataValue: "value", Negate: &negate},
+						},
+					}}},
+				},
+			},
+			want: []string{"spec.forProvider.eventFilters[1].customFilter.metadataFilter.metadataFilterItems[1].negate is deprecated and ignored: the alerting profile schema does not support negating metadata filters"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := warnings(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nwarnings(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
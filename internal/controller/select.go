@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// groupSuffix is the suffix of the API groups of all Dynatrace kinds,
+	// which may be omitted from the patterns of a Selector.
+	groupSuffix = ".dynatrace.crossplane.io"
+
+	errFmtBadPattern  = "invalid controller pattern %q"
+	errFmtRESTMapping = "cannot determine whether the CRD of %s is installed"
+)
+
+// A Selector selects the controllers of the kinds whose group kind, e.g.
+// Profile.alerting.dynatrace.crossplane.io, matches one of its patterns.
+type Selector []string
+
+// NewSelector returns a Selector of the supplied patterns. Patterns are
+// matched case-insensitively, as with path.Match, against the group kind of a
+// kind, with or without the .dynatrace.crossplane.io suffix of its group.
+// For example, "*" selects every kind, "*.alerting" the kinds of the alerting
+// group and "Email.notification" only emails. Patterns may also be separated
+// by commas.
+func NewSelector(patterns ...string) (Selector, error) {
+	s := Selector{}
+	for _, p := range patterns {
+		for _, p := range strings.Split(p, ",") {
+			p = strings.ToLower(strings.TrimSpace(p))
+			if p == "" {
+				continue
+			}
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, errors.Wrapf(err, errFmtBadPattern, p)
+			}
+			s = append(s, p)
+		}
+	}
+	return s, nil
+}
+
+// Selects returns true if the controller of the supplied kind is selected.
+func (s Selector) Selects(gk schema.GroupKind) bool {
+	full := strings.ToLower(gk.String())
+	short := strings.TrimSuffix(full, groupSuffix)
+	for _, p := range s {
+		// Patterns were validated by NewSelector.
+		if ok, _ := path.Match(p, full); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, short); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// installed returns true if the CRD of the supplied kind is installed,
+// according to the supplied mapper.
+func installed(m meta.RESTMapper, gvk schema.GroupVersionKind) (bool, error) {
+	_, err := m.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtRESTMapping, gvk.GroupKind())
+	}
+	return true, nil
+}
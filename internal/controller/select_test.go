@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	alertingv1alpha1 "github.com/crossplane/provider-dynatrace/apis/alerting/v1alpha1"
+	notificationv1alpha1 "github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
+	tagsv1alpha1 "github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+)
+
+func TestSelector(t *testing.T) {
+	all := []schema.GroupVersionKind{
+		alertingv1alpha1.ProfileGroupVersionKind,
+		notificationv1alpha1.EmailGroupVersionKind,
+		notificationv1alpha1.SlackGroupVersionKind,
+		tagsv1alpha1.AutoTagGroupVersionKind,
+	}
+
+	cases := map[string]struct {
+		reason   string
+		patterns []string
+		want     []string
+		err      bool
+	}{
+		"All": {
+			reason:   "A wildcard should select every kind.",
+			patterns: []string{"*"},
+			want:     []string{"Profile", "Email", "Slack", "AutoTag"},
+		},
+		"None": {
+			reason: "No patterns should select no kind.",
+		},
+		"Groups": {
+			reason:   "Patterns should select the kinds of a group, with or without the suffix of its name.",
+			patterns: []string{"*.alerting", "*.notification.dynatrace.crossplane.io"},
+			want:     []string{"Profile", "Email", "Slack"},
+		},
+		"CommaSeparated": {
+			reason:   "Patterns should be split at commas and matched regardless of case.",
+			patterns: []string{"email.notification, AUTOTAG.TAGS"},
+			want:     []string{"Email", "AutoTag"},
+		},
+		"BadPattern": {
+			reason:   "Malformed patterns should be rejected.",
+			patterns: []string{"[alerting"},
+			err:      true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewSelector(tc.patterns...)
+			if (err != nil) != tc.err {
+				t.Fatalf("\n%s\nNewSelector(...): want error %t, got %v", tc.reason, tc.err, err)
+			}
+			var got []string
+			for _, gvk := range all {
+				if s.Selects(gvk.GroupKind()) {
+					got = append(got, gvk.Kind)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ns.Selects(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInstalled(t *testing.T) {
+	m := meta.NewDefaultRESTMapper(nil)
+	m.Add(alertingv1alpha1.ProfileGroupVersionKind, meta.RESTScopeRoot)
+
+	cases := map[string]struct {
+		reason string
+		gvk    schema.GroupVersionKind
+		want   bool
+	}{
+		"Installed": {
+			reason: "A kind known to the API server should be installed.",
+			gvk:    alertingv1alpha1.ProfileGroupVersionKind,
+			want:   true,
+		},
+		"Missing": {
+			reason: "A kind unknown to the API server should not be installed.",
+			gvk:    tagsv1alpha1.AutoTagGroupVersionKind,
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := installed(m, tc.gvk)
+			if err != nil {
+				t.Fatalf("\n%s\ninstalled(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ninstalled(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,243 +17,180 @@ limitations under the License.
 package slack
 
 import (
-	"context"
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/meta"
-	"github.com/crossplane/provider-dynatrace/internal/credentials"
 	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
 	notificationSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications/slack/settings"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"net/http"
-
 	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
-	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
-	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
-	"github.com/crossplane/provider-dynatrace/internal/features"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+	"github.com/crossplane/provider-dynatrace/internal/controller/profile"
+	"github.com/crossplane/provider-dynatrace/internal/credentials"
+	"github.com/crossplane/provider-dynatrace/internal/lateinit"
 )
 
-const (
-	errNotSlack     = "managed resource is not a Slack custom resource"
-	errTrackPCUsage = "cannot track ProviderConfig usage"
-	errGetPC        = "cannot get ProviderConfig"
-	errGetCreds     = "cannot get credentials"
-
-	errNewClient = "cannot create new Service"
-)
+// pathURL is the path of the webhook URL in Slack notification settings.
+const pathURL = "slackNotification.url"
+
+// errNoAlertingProfile is returned when no alerting profile was specified or
+// resolved from a reference or selector.
+const errNoAlertingProfile = "spec.forProvider.alertingProfile is required; specify it, a reference or a selector"
+
+// kind maps Slack managed resources to Slack notification settings objects.
+var kind = &generic.Kind[*v1alpha1.Slack, notifications.Notification]{
+	GroupKind:        v1alpha1.SlackGroupKind,
+	GroupVersionKind: v1alpha1.SlackGroupVersionKind,
+	Name:             func(cr *v1alpha1.Slack) string { return cr.Spec.ForProvider.Name },
+	ToDTO: func(cr *v1alpha1.Slack) (notifications.Notification, error) {
+		return crdToDto(cr.Spec.ForProvider)
+	},
+	LateInitialize: func(cr *v1alpha1.Slack, n notifications.Notification) bool {
+		return lateInitialize(&cr.Spec.ForProvider, n)
+	},
+	Observe: func(cr *v1alpha1.Slack, id string, o *settings20.Object, n notifications.Notification) error {
+		cr.Status.AtProvider = generateObservation(id, o, n, cr.Status.AtProvider.ObfuscatedUrl)
+		if cr.Status.AtProvider.ObfuscatedUrl == nil && n.Slack != nil {
+			cr.Status.AtProvider.ObfuscatedUrl = &n.Slack.URL
+		}
+		return nil
+	},
+	Validate: func(cr *v1alpha1.Slack) field.ErrorList {
+		return validate(cr.Spec.ForProvider)
+	},
+	Spec: func(cr *v1alpha1.Slack) *apisv1alpha1.SettingsObjectSpec {
+		return &cr.Spec.SettingsObjectSpec
+	},
+	Metadata: func(cr *v1alpha1.Slack) *apisv1alpha1.SettingsObjectObservation {
+		return &cr.Status.AtProvider.SettingsObjectObservation
+	},
+	// The API only returns an obfuscated webhook URL; see urlDrift.
+	DiffOptions: []cmp.Option{cmpopts.IgnoreFields(notifications.Notification{}, "LegacyID"), cmpopts.IgnoreFields(notificationSettings.Slack{}, "URL")},
+	Drift:       urlDrift,
+	FromDTO: func(n notifications.Notification) (*v1alpha1.Slack, []string, error) {
+		// The API does not return the webhook URL.
+		return &v1alpha1.Slack{Spec: v1alpha1.SlackSpec{ForProvider: dtoToCrd(n)}}, []string{"spec.forProvider.url"}, nil
+	},
+	Reference: func(cr *v1alpha1.Slack, name generic.NameFunc) {
+		cr.Spec.ForProvider.AlertingProfile, cr.Spec.ForProvider.AlertingProfileRef = profile.Ref(cr.Spec.ForProvider.AlertingProfile, name)
+	},
+}
 
-func newService(data []byte) (settings.CRUDService[*notifications.Notification], error) {
+func newService(data []byte) (settings20.CRUDService[*notifications.Notification], error) {
 	c, err := credentials.Unmarshal(data)
 	if err != nil {
 		return nil, err
 	}
 
-	return notifications.Service(c, notifications.Types.Slack), nil
+	// Notifications of all types share a schema.
+	ofSlack := func(n *notifications.Notification) bool { return n.Type == notifications.Types.Slack }
+	return settings20.NewService(notifications.SchemaID, settings20.NewClient(c), settings20.WithFilter(ofSlack)), nil
 }
 
 // Setup adds a controller that reconciles Slack managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
-	name := managed.ControllerName(v1alpha1.SlackGroupKind)
-
-	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
-	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
-		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
-	}
-
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.SlackGroupVersionKind),
-		managed.WithExternalConnecter(&connector{
-			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newService}),
-		managed.WithLogger(o.Logger.WithValues("controller", name)),
-		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithConnectionPublishers(cps...))
-
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.Slack{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+	return generic.Setup(mgr, o, kind, newService)
 }
 
-// A connector is expected to produce an ExternalClient when its Connect method
-// is called.
-type connector struct {
-	kube         client.Client
-	usage        resource.Tracker
-	newServiceFn func(creds []byte) (settings.CRUDService[*notifications.Notification], error)
+// Exporter returns an Exporter of Slack notifications.
+func Exporter() generic.Exporter {
+	return generic.NewExporter(kind, newService)
 }
 
-// Connect typically produces an ExternalClient by:
-// 1. Tracking that the managed resource is using a ProviderConfig.
-// 2. Getting the managed resource's ProviderConfig.
-// 3. Getting the credentials specified by the ProviderConfig.
-// 4. Using the credentials to form a client.
-func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*v1alpha1.Slack)
-	if !ok {
-		return nil, errors.New(errNotSlack)
-	}
-
-	if err := c.usage.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackPCUsage)
-	}
-
-	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetPC)
-	}
-
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
-	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
-	}
-
-	svc, err := c.newServiceFn(data)
-	if err != nil {
-		return nil, errors.Wrap(err, errNewClient)
-	}
-
-	return &external{service: svc}, nil
+// Planner returns a Planner of Slack notifications.
+func Planner() generic.Planner {
+	return generic.NewPlanner(kind, newService)
 }
 
-// An ExternalClient observes, then either creates, updates, or deletes an
-// external resource to ensure it reflects the managed resource's desired state.
-type external struct {
-	// A 'client' used to connect to the external resource API. In practice this
-	// would be something like an AWS SDK client.
-	service settings.CRUDService[*notifications.Notification]
+// SetupWebhook adds a webhook that validates Slack managed resources.
+func SetupWebhook(mgr ctrl.Manager) error {
+	return generic.SetupWebhook(mgr, kind)
 }
 
-func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.Slack)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotSlack)
+// urlDrift compares the obfuscated webhook URL with the one observed right
+// after the URL was last written, because the API does not return the URL
+// itself. If it changed, the webhook URL was modified in the tenant and is
+// written again.
+func urlDrift(cr *v1alpha1.Slack, observed notifications.Notification) []string {
+	if observed.Slack == nil || cr.Status.AtProvider.ObfuscatedUrl == nil {
+		return nil
 	}
 
-	id := meta.GetExternalName(cr)
-	var n notifications.Notification
-	err := c.service.Get(id, &n)
-	if err != nil {
-		var restError rest.Error
-		if errors.As(err, &restError) {
-			if restError.Code == http.StatusNotFound {
-				return managed.ExternalObservation{ResourceExists: false}, nil
-			}
-		}
-
-		return managed.ExternalObservation{}, err
+	if *cr.Status.AtProvider.ObfuscatedUrl != observed.Slack.URL {
+		cr.Status.AtProvider.ObfuscatedUrl = nil
+		return []string{pathURL}
 	}
 
-	cr.Status.SetConditions(xpv1.Available())
-	cr.Status.AtProvider.ID = id
-
-	if cr.Status.AtProvider.ObfuscatedUrl == nil {
-		cr.Status.AtProvider.ObfuscatedUrl = &n.Slack.URL
-	}
+	return nil
+}
 
-	local := crdToDto(cr.Spec.ForProvider)
-	if diff := cmp.Diff(n, local, cmpopts.IgnoreFields(notifications.Notification{}, "LegacyID"), cmpopts.IgnoreFields(notificationSettings.Slack{}, "URL"), cmpopts.EquateEmpty()); diff != "" {
-		return managed.ExternalObservation{
-			ResourceExists:   true,
-			ResourceUpToDate: false,
-			Diff:             diff,
-		}, nil
+func crdToDto(v v1alpha1.SlackParameters) (notifications.Notification, error) {
+	if v.AlertingProfile == nil {
+		return notifications.Notification{}, errors.New(errNoAlertingProfile)
 	}
 
-	if diff := cmp.Diff(*cr.Status.AtProvider.ObfuscatedUrl, n.Slack.URL); diff != "" {
-		cr.Status.AtProvider.ObfuscatedUrl = nil
-
-		return managed.ExternalObservation{
-			ResourceExists:   true,
-			ResourceUpToDate: false,
-			Diff:             diff,
-		}, nil
-	}
+	return notifications.Notification{
+		Type:      notifications.Types.Slack,
+		Enabled:   v.Enable,
+		Name:      v.Name,
+		ProfileID: *v.AlertingProfile,
 
-	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
+		Slack: &notificationSettings.Slack{
+			URL:     v.Url,
+			Channel: v.Channel,
+			Message: v.Message,
+		},
 	}, nil
 }
 
-func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.Slack)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotSlack)
+// dtoToCrd returns the parameters of the supplied notification, except for
+// the webhook URL the API does not return.
+func dtoToCrd(n notifications.Notification) v1alpha1.SlackParameters {
+	p := v1alpha1.SlackParameters{
+		Name:            n.Name,
+		Enable:          n.Enabled,
+		AlertingProfile: &n.ProfileID,
 	}
 
-	cr.Status.SetConditions(xpv1.Creating())
-
-	n := crdToDto(cr.Spec.ForProvider)
-	apiResp, err := c.service.Create(&n)
-	if err != nil {
-		return managed.ExternalCreation{}, err
+	if sl := n.Slack; sl != nil {
+		p.Channel = sl.Channel
+		p.Message = sl.Message
 	}
 
-	meta.SetExternalName(cr, apiResp.ID)
-
-	return managed.ExternalCreation{}, nil
+	return p
 }
 
-func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*v1alpha1.Slack)
-	if !ok {
-		return managed.ExternalUpdate{}, errors.New(errNotSlack)
-	}
-
-	id := meta.GetExternalName(cr)
-	n := crdToDto(cr.Spec.ForProvider)
-	err := c.service.Update(id, &n)
-	if err != nil {
-		return managed.ExternalUpdate{}, err
-	}
-
-	return managed.ExternalUpdate{}, nil
+// lateInitialize fills unset optional parameters from the observed
+// notification.
+func lateInitialize(in *v1alpha1.SlackParameters, n notifications.Notification) bool {
+	li := &lateinit.Tracker{}
+	lateinit.String(li, &in.AlertingProfile, n.ProfileID)
+	return li.Changed()
 }
 
-func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.Slack)
-	if !ok {
-		return errors.New(errNotSlack)
+// generateObservation returns the observed state of the supplied notification.
+// The obfuscated URL is carried over from the previous observation because it
+// is used to detect changes of the webhook URL.
+func generateObservation(id string, o *settings20.Object, n notifications.Notification, obfuscatedURL *string) v1alpha1.SlackObservation {
+	obs := v1alpha1.SlackObservation{
+		ID:                        id,
+		ObfuscatedUrl:             obfuscatedURL,
+		SettingsObjectObservation: o.Observation(),
+		Enabled:                   n.Enabled,
+		Name:                      n.Name,
+		AlertingProfile:           n.ProfileID,
 	}
 
-	err := c.service.Delete(meta.GetExternalName(cr))
-	if err != nil {
-		return err
+	if sl := n.Slack; sl != nil {
+		obs.Channel = sl.Channel
+		obs.Message = sl.Message
 	}
 
-	return nil
-}
-
-func crdToDto(v v1alpha1.SlackParameters) notifications.Notification {
-	return notifications.Notification{
-		Type:      notifications.Types.Slack,
-		Enabled:   v.Enable,
-		Name:      v.Name,
-		ProfileID: *v.AlertingProfile,
-
-		Slack: &notificationSettings.Slack{
-			URL:     v.Url,
-			Channel: v.Channel,
-			Message: v.Message,
-		},
-	}
+	return obs
 }
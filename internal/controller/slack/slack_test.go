@@ -18,15 +18,27 @@ package slack
 
 import (
 	"context"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
-	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
+	"net/http"
 	"testing"
 
-	"github.com/google/go-cmp/cmp"
-
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
+	notificationSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications/slack/settings"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20/fake"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic/generictest"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -37,14 +49,55 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
+func slackParams() v1alpha1.SlackParameters {
+	profile := "profile-id"
+	return v1alpha1.SlackParameters{
+		Name:            "cool-slack",
+		Enable:          true,
+		Url:             "https://example.org/hook",
+		Channel:         "#ops",
+		Message:         "{ProblemTitle}",
+		AlertingProfile: &profile,
+	}
+}
+
+func slack(id string) *v1alpha1.Slack {
+	cr := &v1alpha1.Slack{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		Spec:       v1alpha1.SlackSpec{ForProvider: slackParams()},
+	}
+	if id != "" {
+		meta.SetExternalName(cr, id)
+	}
+	return cr
+}
+
+func desired(t *testing.T) notifications.Notification {
+	t.Helper()
+	n, err := crdToDto(slackParams())
+	if err != nil {
+		t.Fatalf("crdToDto(...): %v", err)
+	}
+	return n
+}
+
 func TestObserve(t *testing.T) {
-	type fields struct {
-		service settings.CRUDService[*notifications.Notification]
+	api := fake.NewServer()
+	defer api.Close()
+
+	svc, err := newService(api.Credentials())
+	if err != nil {
+		t.Fatalf("newService(...): %v", err)
 	}
 
+	upToDate := api.Create(notifications.SchemaID, fake.ScopeEnvironment, desired(t))
+	changed := desired(t)
+	changed.Slack.Channel = "#dev"
+	drifted := api.Create(notifications.SchemaID, fake.ScopeEnvironment, changed)
+
 	type args struct {
-		ctx context.Context
-		mg  resource.Managed
+		fault *fake.Fault
+		mg    resource.Managed
 	}
 
 	type want struct {
@@ -54,23 +107,234 @@ func TestObserve(t *testing.T) {
 
 	cases := map[string]struct {
 		reason string
-		fields fields
 		args   args
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NotFound": {
+			reason: "A notification that does not exist should be reported as such.",
+			args:   args{mg: slack("missing")},
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"UpToDate": {
+			reason: "A notification that matches the desired state should be up to date.",
+			args:   args{mg: slack(upToDate)},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}},
+		},
+		"Drifted": {
+			reason: "A notification that differs from the desired state should not be up to date.",
+			args:   args{mg: slack(drifted)},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}},
+		},
+		"ServerError": {
+			reason: "Errors reading the notification should be returned.",
+			args: args{
+				fault: &fake.Fault{Method: http.MethodGet, Code: http.StatusInternalServerError},
+				mg:    slack(upToDate),
+			},
+			want: want{err: rest.Error{Code: http.StatusInternalServerError, Message: http.StatusText(http.StatusInternalServerError)}},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{service: tc.fields.service}
-			got, err := e.Observe(tc.args.ctx, tc.args.mg)
+			if tc.args.fault != nil {
+				api.Inject(*tc.args.fault)
+			}
+			e := generic.NewExternal(kind, svc)
+			got, err := e.Observe(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+			if diff := cmp.Diff(tc.want.o, got, cmpopts.IgnoreFields(managed.ExternalObservation{}, "Diff")); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
 		})
 	}
 }
+
+func TestReconcile(t *testing.T) {
+	env := generictest.New(t, kind, newService)
+	cr := slack("")
+	env.Create(t, cr)
+
+	env.Reconcile(t, cr)
+	id := meta.GetExternalName(cr)
+	checkValue(t, env, id, desired(t))
+
+	env.Reconcile(t, cr)
+	generictest.CheckCondition(t, cr, xpv1.Available())
+	generictest.CheckCondition(t, cr, xpv1.ReconcileSuccess())
+
+	// Throttled requests are reported and retried.
+	env.API.Inject(fake.Fault{Method: http.MethodGet, Code: http.StatusTooManyRequests})
+	env.Reconcile(t, cr)
+	generictest.CheckCondition(t, cr, apisv1alpha1.APIRequestFailed(apisv1alpha1.ReasonThrottled, ""))
+	generictest.CheckCondition(t, cr, xpv1.ReconcileError(errors.New("throttled")))
+
+	env.Reconcile(t, cr)
+	generictest.CheckCondition(t, cr, apisv1alpha1.APIRequestSucceeded())
+	generictest.CheckCondition(t, cr, xpv1.ReconcileSuccess())
+
+	// A notification modified by someone else while it is being updated is
+	// not overwritten until it was observed again.
+	changed := desired(t)
+	changed.Slack.Channel = "#dev"
+	env.API.Update(id, changed)
+	env.API.Inject(fake.Fault{Method: http.MethodPut, Path: "/api/v2/settings/objects/" + id, Code: http.StatusConflict})
+	env.Reconcile(t, cr)
+	checkValue(t, env, id, changed)
+	generictest.CheckCondition(t, cr, xpv1.ReconcileError(errors.New("conflict")))
+
+	env.Reconcile(t, cr)
+	checkValue(t, env, id, desired(t))
+
+	// A notification deleted by someone else is created again.
+	env.API.Delete(id)
+	env.Reconcile(t, cr)
+	recreated := meta.GetExternalName(cr)
+	if recreated == id {
+		t.Fatalf("Reconcile(...): deleted notification %q was not created again", id)
+	}
+	checkValue(t, env, recreated, desired(t))
+
+	env.Delete(t, cr)
+	env.Reconcile(t, cr)
+	if _, ok := env.API.Object(recreated); ok {
+		t.Fatalf("Reconcile(...): notification was not deleted")
+	}
+	if env.Reconcile(t, cr) {
+		t.Errorf("Reconcile(...): managed resource of deleted notification still exists")
+	}
+}
+
+// checkValue checks that the value of the settings object with the supplied
+// ID is the supplied notification.
+func checkValue(t *testing.T, env *generictest.Environment, id string, want notifications.Notification) {
+	t.Helper()
+	got := notifications.Notification{}
+	env.Value(t, id, &got)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Reconcile(...): -want notification, +got:\n%s\n", diff)
+	}
+}
+
+func TestCrdToDto(t *testing.T) {
+	profile := "profile-id"
+
+	type want struct {
+		n   notifications.Notification
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.SlackParameters
+		want   want
+	}{
+		"WithAlertingProfile": {
+			reason: "A notification with an alerting profile should be converted.",
+			in:     v1alpha1.SlackParameters{Enable: true, Name: "cool", Url: "https://example.org/hook", Channel: "#ops", Message: "{ProblemTitle}", AlertingProfile: &profile},
+			want: want{n: notifications.Notification{
+				Type:      notifications.Types.Slack,
+				Enabled:   true,
+				Name:      "cool",
+				ProfileID: profile,
+				Slack:     &notificationSettings.Slack{URL: "https://example.org/hook", Channel: "#ops", Message: "{ProblemTitle}"},
+			}},
+		},
+		"NilAlertingProfile": {
+			reason: "A notification whose alerting profile was neither specified nor resolved should return an error rather than panic.",
+			in:     v1alpha1.SlackParameters{Enable: true, Name: "cool", Url: "https://example.org/hook", Channel: "#ops", Message: "{ProblemTitle}"},
+			want:   want{err: errors.New(errNoAlertingProfile)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			n, err := crdToDto(tc.in)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncrdToDto(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.n, n); diff != "" {
+				t.Errorf("\n%s\ncrdToDto(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	profile := "profile-id"
+	obfuscated := "https://hooks.slack.com/services/***"
+
+	params := v1alpha1.SlackParameters{
+		Name:            "cool-slack",
+		Enable:          true,
+		Url:             "https://hooks.slack.com/services/secret",
+		Channel:         "#alerts",
+		Message:         "{ProblemTitle}",
+		AlertingProfile: &profile,
+	}
+
+	observed := func(url string) notifications.Notification {
+		return notifications.Notification{
+			Type:      notifications.Types.Slack,
+			Enabled:   true,
+			Name:      "cool-slack",
+			ProfileID: profile,
+			Slack:     &notificationSettings.Slack{URL: url, Channel: "#alerts", Message: "{ProblemTitle}"},
+		}
+	}
+
+	cases := map[string]struct {
+		reason   string
+		observed notifications.Notification
+		want     []string
+	}{
+		"Unchanged": {
+			reason:   "A notification whose obfuscated URL did not change should not drift.",
+			observed: observed(obfuscated),
+		},
+		"URLChanged": {
+			reason:   "A notification whose obfuscated URL changed should drift.",
+			observed: observed("https://hooks.slack.com/services/###"),
+			want:     []string{pathURL},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.Slack{Spec: v1alpha1.SlackSpec{ForProvider: *params.DeepCopy()}}
+			cr.Status.AtProvider.ObfuscatedUrl = &obfuscated
+			kind.LateInitialize(cr, tc.observed)
+			desired, err := kind.ToDTO(cr)
+			if err != nil {
+				t.Fatalf("kind.ToDTO(...): %v", err)
+			}
+			declared, err := crdToDto(params)
+			if err != nil {
+				t.Fatalf("crdToDto(...): %v", err)
+			}
+			if diff := cmp.Diff(desired, declared); diff != "" {
+				t.Errorf("\n%s\nkind.ToDTO(...): -after late-init, +declared:\n%s\n", tc.reason, diff)
+			}
+			got := kind.Compare(cr, tc.observed, desired)
+			if diff := cmp.Diff(tc.want, got.Paths); diff != "" {
+				t.Errorf("\n%s\nkind.Compare(...): -want drifted paths, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDtoToCrd(t *testing.T) {
+	n, err := crdToDto(slackParams())
+	if err != nil {
+		t.Fatalf("crdToDto(...): %v", err)
+	}
+
+	// The webhook URL is not exported, because the API does not return it.
+	want := slackParams()
+	want.Url = ""
+	if diff := cmp.Diff(want, dtoToCrd(n)); diff != "" {
+		t.Errorf("dtoToCrd(...): -want, +got:\n%s\n", diff)
+	}
+}
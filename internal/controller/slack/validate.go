@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
+)
+
+// validate returns the constraints of the Slack notification schema the
+// supplied parameters violate.
+func validate(in v1alpha1.SlackParameters) field.ErrorList {
+	p := field.NewPath("spec", "forProvider")
+	errs := field.ErrorList{}
+
+	if strings.TrimSpace(in.Name) == "" {
+		errs = append(errs, field.Required(p.Child("name"), "the name of the notification must not be blank"))
+	}
+	if in.Channel == "" {
+		errs = append(errs, field.Required(p.Child("channel"), "the channel to post to must not be empty"))
+	}
+	if in.Message == "" {
+		errs = append(errs, field.Required(p.Child("message"), "the content of the message must not be empty"))
+	}
+
+	// The URL is a secret, so it is never included in the error.
+	if u, err := url.Parse(in.Url); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, field.Invalid(p.Child("url"), "", "must be an http(s) URL"))
+	}
+
+	if in.AlertingProfile == nil && in.AlertingProfileRef == nil && in.AlertingProfileSelector == nil {
+		errs = append(errs, field.Required(p.Child("alertingProfile"), "an alerting profile, a reference or a selector must be specified"))
+	}
+
+	return errs
+}
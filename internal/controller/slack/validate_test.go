@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	p := field.NewPath("spec", "forProvider")
+	profile := "some-profile"
+
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.SlackParameters
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "A notification with a webhook URL and an alerting profile should be valid.",
+			in: v1alpha1.SlackParameters{
+				Name:            "cool-slack",
+				Url:             "https://hooks.slack.com/services/secret",
+				Channel:         "#ops",
+				Message:         "{ProblemTitle}",
+				AlertingProfile: &profile,
+			},
+			want: field.ErrorList{},
+		},
+		"InvalidURL": {
+			reason: "The webhook URL should be an http(s) URL and never be echoed.",
+			in: v1alpha1.SlackParameters{
+				Name:            "cool-slack",
+				Url:             "hooks.slack.com/services/secret",
+				Channel:         "#ops",
+				Message:         "{ProblemTitle}",
+				AlertingProfile: &profile,
+			},
+			want: field.ErrorList{field.Invalid(p.Child("url"), "", "must be an http(s) URL")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := validate(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
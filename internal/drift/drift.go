@@ -0,0 +1,305 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drift compares observed and desired settings objects semantically
+// and reports the paths of the fields that drifted.
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+)
+
+// A Result describes the drift between an observed and a desired settings
+// object.
+type Result struct {
+	// Paths of the fields that drifted, using the property names of the
+	// Settings 2.0 schema, e.g. "emailNotification.ccRecipients" or
+	// "rules[0].enabled". Elements of unordered lists are reported without
+	// an index.
+	Paths []string
+
+	// Diff is a human readable report of the drift.
+	Diff string
+}
+
+// Drifted returns true if any field drifted.
+func (r Result) Drifted() bool {
+	return len(r.Paths) > 0
+}
+
+// Compare the observed and desired settings objects. Besides the supplied
+// options, unset values are considered equal to their zero value, because
+// the Settings 2.0 API fills in defaults for omitted properties.
+func Compare(observed, desired any, o ...cmp.Option) Result {
+	r := &reporter{}
+	opts := append([]cmp.Option{EquateDefaults()}, o...)
+
+	if cmp.Equal(observed, desired, append(opts, cmp.Reporter(r))...) {
+		return Result{}
+	}
+
+	return Result{Paths: r.result(), Diff: cmp.Diff(observed, desired, opts...)}
+}
+
+// EquateDefaults returns an option that considers empty slices and maps equal
+// to nil ones, and nil pointers equal to pointers to a zero value.
+func EquateDefaults() cmp.Option {
+	return cmp.Options{
+		cmpopts.EquateEmpty(),
+		cmp.FilterValues(func(x, y any) bool {
+			vx, vy := reflect.ValueOf(x), reflect.ValueOf(y)
+			if !vx.IsValid() || !vy.IsValid() || vx.Type() != vy.Type() || vx.Kind() != reflect.Pointer {
+				return false
+			}
+			return isNilOrZero(vx) && isNilOrZero(vy)
+		}, cmp.Comparer(func(_, _ any) bool { return true })),
+	}
+}
+
+func isNilOrZero(v reflect.Value) bool {
+	return v.IsNil() || v.Elem().IsZero()
+}
+
+// Unordered returns an option that ignores the order of all slices of T, for
+// properties the Settings 2.0 schema declares as sets. Elements are ordered
+// by a canonical JSON encoding that omits zero values and ignores the order
+// of nested lists, so that an observed element with server-side defaults or a
+// reordered nested set sorts like the desired one.
+func Unordered[T any]() cmp.Option {
+	return cmpopts.SortSlices(func(a, b T) bool {
+		return key(a) < key(b)
+	})
+}
+
+func key(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%#v", v)
+	}
+	var j any
+	if err := json.Unmarshal(b, &j); err != nil {
+		return string(b)
+	}
+	return encode(canonical(j))
+}
+
+func encode(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// canonical returns the supplied JSON value without zero values and with all
+// arrays sorted by the canonical encoding of their elements.
+func canonical(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := map[string]any{}
+		for k, e := range v {
+			if e = canonical(e); !isZero(e) {
+				out[k] = e
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = canonical(e)
+		}
+		sort.SliceStable(out, func(i, j int) bool { return encode(out[i]) < encode(out[j]) })
+		return out
+	}
+	return v
+}
+
+func isZero(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// IgnorePaths returns an option that ignores the fields at the supplied
+// paths, including everything nested below them. Paths use the same notation
+// as Result.Paths, except that slice indices may be omitted to match every
+// element, e.g. "rules.attributeRule.conditions".
+func IgnorePaths(paths ...string) cmp.Option {
+	if len(paths) == 0 {
+		return cmp.Options{}
+	}
+	return cmp.FilterPath(func(p cmp.Path) bool {
+		full, unindexed := pathString(p, true), pathString(p, false)
+		for _, ignored := range paths {
+			if isPrefix(ignored, full) || isPrefix(ignored, unindexed) {
+				return true
+			}
+		}
+		return false
+	}, cmp.Ignore())
+}
+
+func isPrefix(prefix, path string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	rest := path[len(prefix):]
+	return rest == "" || rest[0] == '.' || rest[0] == '['
+}
+
+// IgnoredPaths returns the paths listed in the ignore-drift annotation of the
+// supplied object.
+func IgnoredPaths(o metav1.Object) []string {
+	var paths []string
+	for _, p := range strings.Split(o.GetAnnotations()[apisv1alpha1.AnnotationKeyIgnoreDrift], ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// A reporter records the paths of all unequal leaves of a comparison.
+type reporter struct {
+	path  cmp.Path
+	paths map[string]bool
+}
+
+func (r *reporter) PushStep(ps cmp.PathStep) {
+	r.path = append(r.path, ps)
+}
+
+func (r *reporter) Report(rs cmp.Result) {
+	if rs.Equal() {
+		return
+	}
+	if r.paths == nil {
+		r.paths = map[string]bool{}
+	}
+	r.paths[pathString(r.path, true)] = true
+}
+
+func (r *reporter) PopStep() {
+	r.path = r.path[:len(r.path)-1]
+}
+
+func (r *reporter) result() []string {
+	paths := make([]string, 0, len(r.paths))
+	for p := range r.paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// pathString formats the supplied path using the JSON names of struct
+// fields. Slice indices are only included if indexed is true.
+func pathString(p cmp.Path, indexed bool) string {
+	b := &strings.Builder{}
+	sorted := false
+	for i, s := range p {
+		switch s := s.(type) {
+		case cmp.Transform:
+			// Elements of unordered slices are compared after sorting, so
+			// their indices are meaningless.
+			sorted = true
+		case cmp.StructField:
+			sorted = false
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(jsonName(p[i-1].Type(), s.Name()))
+		case cmp.SliceIndex:
+			if sorted || !indexed {
+				sorted = false
+				continue
+			}
+			kx, ky := s.SplitKeys()
+			k := kx
+			if k < 0 {
+				k = ky
+			}
+			fmt.Fprintf(b, "[%d]", k)
+		case cmp.MapIndex:
+			sorted = false
+			fmt.Fprintf(b, "[%v]", s.Key())
+		}
+	}
+	if b.Len() == 0 {
+		return "."
+	}
+	return b.String()
+}
+
+func jsonName(t reflect.Type, field string) string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return field
+	}
+	f, ok := t.FieldByName(field)
+	if !ok {
+		return field
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field
+	}
+	return name
+}
+
+// With returns a copy of the result that additionally reports the supplied
+// paths as drifted, e.g. because they cannot be compared field by field.
+func (r Result) With(paths ...string) Result {
+	if len(paths) == 0 {
+		return r
+	}
+
+	seen := map[string]bool{}
+	out := Result{Diff: r.Diff}
+	for _, p := range append(append([]string{}, r.Paths...), paths...) {
+		if !seen[p] {
+			seen[p] = true
+			out.Paths = append(out.Paths, p)
+		}
+	}
+	sort.Strings(out.Paths)
+
+	for _, p := range paths {
+		out.Diff += fmt.Sprintf("%s: drifted\n", p)
+	}
+	return out
+}
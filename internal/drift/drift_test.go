@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+)
+
+type condition struct {
+	Key   string  `json:"key"`
+	Value *string `json:"value,omitempty"`
+}
+
+type rule struct {
+	Enabled    bool         `json:"enabled"`
+	Conditions []*condition `json:"conditions"`
+}
+
+type object struct {
+	Name       string   `json:"name"`
+	Recipients []string `json:"recipients,omitempty"`
+	Enabled    *bool    `json:"enabled,omitempty"`
+	Rules      []rule   `json:"rules"`
+}
+
+func TestCompare(t *testing.T) {
+	value := "v"
+	empty := ""
+	disabled := false
+
+	type args struct {
+		observed object
+		desired  object
+		o        []cmp.Option
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"Equal": {
+			reason: "Equal objects should not drift.",
+			args: args{
+				observed: object{Name: "a", Recipients: []string{"x"}},
+				desired:  object{Name: "a", Recipients: []string{"x"}},
+			},
+		},
+		"Defaults": {
+			reason: "Unset values should equal their zero value.",
+			args: args{
+				observed: object{Name: "a", Recipients: []string{}, Enabled: &disabled, Rules: []rule{{Conditions: []*condition{{Key: "k", Value: &empty}}}}},
+				desired:  object{Name: "a", Rules: []rule{{Conditions: []*condition{{Key: "k"}}}}},
+			},
+		},
+		"Unordered": {
+			reason: "The order of unordered lists should be ignored.",
+			args: args{
+				observed: object{Recipients: []string{"y", "x"}, Rules: []rule{{Conditions: []*condition{{Key: "b"}, {Key: "a", Value: &value}}}}},
+				desired:  object{Recipients: []string{"x", "y"}, Rules: []rule{{Conditions: []*condition{{Key: "a", Value: &value}, {Key: "b"}}}}},
+				o:        []cmp.Option{Unordered[string](), Unordered[*condition]()},
+			},
+		},
+		"UnorderedDrift": {
+			reason: "Drifted elements of unordered lists should be reported without an index.",
+			args: args{
+				observed: object{Recipients: []string{"z", "x"}},
+				desired:  object{Recipients: []string{"x", "y"}},
+				o:        []cmp.Option{Unordered[string]()},
+			},
+			want: []string{"recipients"},
+		},
+		"Ordered": {
+			reason: "The order of other lists should be considered drift.",
+			args: args{
+				observed: object{Recipients: []string{"y", "x"}},
+				desired:  object{Recipients: []string{"x", "y"}},
+			},
+			want: []string{"recipients[0]", "recipients[1]"},
+		},
+		"Paths": {
+			reason: "Drifted fields should be reported by their JSON paths.",
+			args: args{
+				observed: object{Name: "a", Rules: []rule{{Enabled: true, Conditions: []*condition{{Key: "k", Value: &value}}}}},
+				desired:  object{Name: "b", Rules: []rule{{Enabled: true, Conditions: []*condition{{Key: "k"}}}}},
+			},
+			want: []string{"name", "rules[0].conditions[0].value"},
+		},
+		"IgnorePaths": {
+			reason: "Differences at or below ignored paths should not drift, with or without indices.",
+			args: args{
+				observed: object{Name: "a", Enabled: &disabled, Rules: []rule{{Enabled: true, Conditions: []*condition{{Key: "k"}}}}},
+				desired:  object{Name: "b", Rules: []rule{{Conditions: []*condition{{Key: "l"}}}}},
+				o:        []cmp.Option{IgnorePaths("name", "rules.conditions", "rules[0].enabled")},
+			},
+		},
+		"IgnorePathsPrefix": {
+			reason: "Ignored paths should only match whole property names.",
+			args: args{
+				observed: object{Name: "a", Rules: []rule{{Enabled: true}}},
+				desired:  object{Name: "b"},
+				o:        []cmp.Option{IgnorePaths("nam", "rule")},
+			},
+			want: []string{"name", "rules"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Compare(tc.args.observed, tc.args.desired, tc.args.o...)
+			if diff := cmp.Diff(tc.want, got.Paths); diff != "" {
+				t.Errorf("\n%s\nCompare(...): -want paths, +got paths:\n%s\n", tc.reason, diff)
+			}
+			if got.Drifted() != (got.Diff != "") {
+				t.Errorf("\n%s\nCompare(...): Drifted() is %t, but Diff is %q\n", tc.reason, got.Drifted(), got.Diff)
+			}
+		})
+	}
+}
+
+func TestWith(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		r      Result
+		paths  []string
+		want   []string
+	}{
+		"NoPaths": {
+			reason: "Adding no paths should not drift.",
+		},
+		"Merged": {
+			reason: "Added paths should be merged with the compared ones.",
+			r:      Result{Paths: []string{"name", "url"}, Diff: "diff"},
+			paths:  []string{"url", "enabled"},
+			want:   []string{"enabled", "name", "url"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.r.With(tc.paths...)
+			if diff := cmp.Diff(tc.want, got.Paths); diff != "" {
+				t.Errorf("\n%s\nWith(...): -want paths, +got paths:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIgnoredPaths(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        []string
+	}{
+		"NoAnnotation": {
+			reason: "No paths should be ignored without the annotation.",
+		},
+		"Paths": {
+			reason: "Paths should be split and trimmed, skipping empty ones.",
+			annotations: map[string]string{
+				apisv1alpha1.AnnotationKeyIgnoreDrift: " name, rules.enabled,,",
+			},
+			want: []string{"name", "rules.enabled"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IgnoredPaths(&metav1.ObjectMeta{Annotations: tc.annotations})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIgnoredPaths(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
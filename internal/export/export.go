@@ -0,0 +1,188 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package export generates manifests of managed resources that desire the
+// settings objects that exist in a Dynatrace tenant, e.g. to onboard a tenant
+// that was configured by hand.
+package export
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+)
+
+const (
+	errFmtExport = "cannot export %s"
+	errFmtWrite  = "cannot write manifest of %s"
+
+	// defaultName is the name of managed resources whose settings object
+	// name contains no character allowed in Kubernetes names.
+	defaultName = "object"
+)
+
+// invalid matches runs of characters that are not allowed in Kubernetes
+// names.
+var invalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+// An Exported managed resource and the settings object it desires.
+type Exported struct {
+	generic.Exported
+
+	// GroupKind of the managed resource.
+	GroupKind string
+}
+
+// Warning returns a message describing what must be done before the exported
+// managed resource can be applied, or an empty string if nothing.
+func (e Exported) Warning() string {
+	if len(e.Incomplete) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s %s (settings object %s): set %s", e.GroupKind, e.Managed.GetName(), e.ID, strings.Join(e.Incomplete, ", "))
+}
+
+// Export the settings objects of all supplied kinds, in order, using the
+// supplied credentials. Each managed resource is named after its settings
+// object, annotated with the object ID as its external name and references
+// the ProviderConfig of the supplied name. References between settings
+// objects are replaced by references between the managed resources.
+func Export(creds []byte, exporters []generic.Exporter, providerConfig string) ([]Exported, error) {
+	var out []Exported
+	names := map[string]map[string]string{}
+
+	for _, e := range exporters {
+		exported, err := e.Export(creds)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtExport, e.GroupKind())
+		}
+
+		ids := map[string]string{}
+		used := map[string]bool{}
+		for _, x := range exported {
+			name := uniqueName(used, x.Name)
+			x.Managed.SetName(name)
+			meta.SetExternalName(x.Managed, x.ID)
+			x.Managed.SetProviderConfigReference(&xpv1.Reference{Name: providerConfig})
+			ids[x.ID] = name
+			out = append(out, Exported{Exported: x, GroupKind: e.GroupKind()})
+		}
+		names[e.GroupKind()] = ids
+	}
+
+	name := func(groupKind, id string) (string, bool) {
+		n, ok := names[groupKind][id]
+		return n, ok
+	}
+	for _, x := range out {
+		for _, e := range exporters {
+			if e.GroupKind() == x.GroupKind {
+				e.Reference(x.Managed, name)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// Name returns a Kubernetes name derived from the supplied settings object
+// name.
+func Name(s string) string {
+	n := strings.Trim(invalid.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(n) > validation.DNS1123LabelMaxLength {
+		n = strings.TrimRight(n[:validation.DNS1123LabelMaxLength], "-")
+	}
+	if n == "" {
+		return defaultName
+	}
+	return n
+}
+
+// uniqueName returns a Kubernetes name derived from the supplied settings
+// object name that is not used yet, and marks it used.
+func uniqueName(used map[string]bool, s string) string {
+	base := Name(s)
+	n := base
+	for i := 2; used[n]; i++ {
+		suffix := "-" + strconv.Itoa(i)
+		prefix := base
+		if l := validation.DNS1123LabelMaxLength - len(suffix); len(prefix) > l {
+			prefix = strings.TrimRight(prefix[:l], "-")
+		}
+		n = prefix + suffix
+	}
+	used[n] = true
+	return n
+}
+
+// Write the manifests of the supplied managed resources as a multi-document
+// YAML stream. Their status and unset fields are omitted.
+func Write(w io.Writer, mgs []resource.Managed) error {
+	for i, mg := range mgs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mg)
+		if err != nil {
+			return errors.Wrapf(err, errFmtWrite, mg.GetName())
+		}
+		delete(u, "status")
+		if m, ok := u["metadata"].(map[string]any); ok {
+			delete(m, "creationTimestamp")
+		}
+		prune(u)
+
+		b, err := yaml.Marshal(u)
+		if err != nil {
+			return errors.Wrapf(err, errFmtWrite, mg.GetName())
+		}
+		if i > 0 {
+			b = append([]byte("---\n"), b...)
+		}
+		if _, err := w.Write(b); err != nil {
+			return errors.Wrapf(err, errFmtWrite, mg.GetName())
+		}
+	}
+	return nil
+}
+
+// prune removes null values from the supplied object, recursively. Optional
+// parameters that are not omitted when empty are null if unset.
+func prune(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if e == nil {
+				delete(v, k)
+				continue
+			}
+			prune(e)
+		}
+	case []any:
+		for _, e := range v {
+			prune(e)
+		}
+	}
+}
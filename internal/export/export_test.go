@@ -0,0 +1,218 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile"
+	profileSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile/settings"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
+	emailSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications/email/settings"
+	slackSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications/slack/settings"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging"
+	autotaggingSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging/settings"
+	"github.com/google/go-cmp/cmp"
+
+	alertingv1alpha1 "github.com/crossplane/provider-dynatrace/apis/alerting/v1alpha1"
+	notificationv1alpha1 "github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
+	tagsv1alpha1 "github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20/fake"
+	dynatrace "github.com/crossplane/provider-dynatrace/internal/controller"
+)
+
+func TestName(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     string
+		want   string
+	}{
+		"Lowercased": {
+			reason: "Upper case letters should be lowercased.",
+			in:     "Prod",
+			want:   "prod",
+		},
+		"Replaced": {
+			reason: "Runs of invalid characters should be replaced with a single dash and trimmed.",
+			in:     "  Team A / Prod (EU)!",
+			want:   "team-a-prod-eu",
+		},
+		"Truncated": {
+			reason: "Names should be truncated to 63 characters without a trailing dash.",
+			in:     strings.Repeat("a", 62) + "-b",
+			want:   strings.Repeat("a", 62),
+		},
+		"Empty": {
+			reason: "Names without valid characters should fall back to a default.",
+			in:     "???",
+			want:   defaultName,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Name(tc.in)); diff != "" {
+				t.Errorf("\n%s\nName(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// summary is the part of an exported managed resource these tests check.
+type summary struct {
+	GroupKind    string
+	Name         string
+	ExternalName string
+	Profile      *string
+	ProfileRef   *xpv1.Reference
+	Warning      string
+}
+
+func summarize(x Exported) summary {
+	s := summary{
+		GroupKind:    x.GroupKind,
+		Name:         x.Managed.GetName(),
+		ExternalName: meta.GetExternalName(x.Managed),
+		Warning:      x.Warning(),
+	}
+	switch cr := x.Managed.(type) {
+	case *notificationv1alpha1.Email:
+		s.Profile, s.ProfileRef = cr.Spec.ForProvider.AlertingProfile, cr.Spec.ForProvider.AlertingProfileRef
+	case *notificationv1alpha1.Slack:
+		s.Profile, s.ProfileRef = cr.Spec.ForProvider.AlertingProfile, cr.Spec.ForProvider.AlertingProfileRef
+	}
+	return s
+}
+
+func TestExport(t *testing.T) {
+	api := fake.NewServer()
+	defer api.Close()
+
+	prod := api.Create(profile.SchemaID, fake.ScopeEnvironment, &profileSettings.Profile{Name: "Prod"})
+	// Event types Dynatrace added since cannot be exported, but should not
+	// fail the export.
+	prod2 := api.Create(profile.SchemaID, fake.ScopeEnvironment, &profileSettings.Profile{Name: "prod", EventFilters: profileSettings.EventFilters{{
+		Type:       profileSettings.EventFilterTypes.Predefined,
+		Predefined: &profileSettings.PredefinedEventFilter{EventType: "SOMETHING_NEW"},
+	}}})
+	email := api.Create(notifications.SchemaID, fake.ScopeEnvironment, &notifications.Notification{
+		Type:      notifications.Types.Email,
+		Enabled:   true,
+		Name:      "Ops Email",
+		ProfileID: prod,
+		Email:     &emailSettings.Email{Subject: "Problem", Body: "{ProblemDetailsHTML}", Recipients: []string{"ops@example.org"}},
+	})
+	slack := api.Create(notifications.SchemaID, fake.ScopeEnvironment, &notifications.Notification{
+		Type:      notifications.Types.Slack,
+		Enabled:   true,
+		Name:      "Ops Slack",
+		ProfileID: "unknown-profile",
+		Slack:     &slackSettings.Slack{URL: "https://hooks.slack.com/***", Channel: "#ops", Message: "{ProblemTitle}"},
+	})
+	tag := api.Create(autotagging.SchemaID, fake.ScopeEnvironment, &autotaggingSettings.Settings{Name: "Owner", Rules: autotaggingSettings.Rules{}})
+
+	unknown := "unknown-profile"
+	want := []summary{
+		{GroupKind: alertingv1alpha1.ProfileGroupKind, Name: "prod", ExternalName: prod},
+		{
+			GroupKind:    alertingv1alpha1.ProfileGroupKind,
+			Name:         "prod-2",
+			ExternalName: prod2,
+			Warning:      alertingv1alpha1.ProfileGroupKind + " prod-2 (settings object " + prod2 + `): set spec.forProvider.eventFilters[0]: unsupported event type "SOMETHING_NEW"`,
+		},
+		{GroupKind: notificationv1alpha1.EmailGroupKind, Name: "ops-email", ExternalName: email, ProfileRef: &xpv1.Reference{Name: "prod"}},
+		{
+			GroupKind:    notificationv1alpha1.SlackGroupKind,
+			Name:         "ops-slack",
+			ExternalName: slack,
+			Profile:      &unknown,
+			Warning:      notificationv1alpha1.SlackGroupKind + " ops-slack (settings object " + slack + "): set spec.forProvider.url",
+		},
+		{GroupKind: tagsv1alpha1.AutoTagGroupKind, Name: "owner", ExternalName: tag},
+	}
+
+	exported, err := Export(api.Credentials(), dynatrace.Exporters(), "tenant")
+	if err != nil {
+		t.Fatalf("Export(...): %v", err)
+	}
+
+	got := make([]summary, len(exported))
+	for i, x := range exported {
+		got[i] = summarize(x)
+		if pc := x.Managed.GetProviderConfigReference(); pc == nil || pc.Name != "tenant" {
+			t.Errorf("Export(...): %s: want providerConfigRef tenant, got %v", x.Managed.GetName(), pc)
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Export(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	api := fake.NewServer()
+	defer api.Close()
+
+	id := api.Create(profile.SchemaID, fake.ScopeEnvironment, &profileSettings.Profile{
+		Name: "Prod",
+		SeverityRules: profileSettings.SeverityRules{
+			{SeverityLevel: profileSettings.SeverityLevels.Availability, TagFilterIncludeMode: profileSettings.TagFilterIncludeModes.IncludeAny, Tags: []string{"env:prod"}},
+		},
+	})
+
+	exported, err := Export(api.Credentials(), dynatrace.Exporters()[:1], "default")
+	if err != nil {
+		t.Fatalf("Export(...): %v", err)
+	}
+	mgs := make([]resource.Managed, len(exported))
+	for i, x := range exported {
+		mgs[i] = x.Managed
+	}
+	mgs = append(mgs, mgs[0])
+
+	manifest := `apiVersion: alerting.dynatrace.crossplane.io/v1alpha1
+kind: Profile
+metadata:
+  annotations:
+    crossplane.io/external-name: ` + id + `
+  name: prod
+spec:
+  forProvider:
+    name: Prod
+    severityRules:
+    - delayInMinutes: 0
+      severityLevel: AVAILABILITY
+      tagFilter:
+      - env:prod
+      tagFilterIncludeMode: INCLUDE_ANY
+  providerConfigRef:
+    name: default
+`
+	want := manifest + "---\n" + manifest
+
+	b := &bytes.Buffer{}
+	if err := Write(b, mgs); err != nil {
+		t.Fatalf("Write(...): %v", err)
+	}
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("Write(...): -want, +got:\n%s\n", diff)
+	}
+}
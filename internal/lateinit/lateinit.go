@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lateinit fills unset optional parameters of managed resources from
+// the values observed in the Dynatrace tenant.
+package lateinit
+
+// A Tracker records whether any parameter was late-initialized.
+type Tracker struct {
+	changed bool
+}
+
+// Changed returns true if any parameter was late-initialized.
+func (t *Tracker) Changed() bool {
+	return t.changed
+}
+
+// Ptr sets in to from if in is nil and from is not.
+func Ptr[T any](t *Tracker, in **T, from *T) {
+	if *in != nil || from == nil {
+		return
+	}
+	v := *from
+	*in = &v
+	t.changed = true
+}
+
+// String sets in to from if in is nil and from is not empty.
+func String[T ~string](t *Tracker, in **string, from T) {
+	if *in != nil || from == "" {
+		return
+	}
+	v := string(from)
+	*in = &v
+	t.changed = true
+}
+
+// Slice sets in to from if in is nil and from is not empty.
+func Slice[T any](t *Tracker, in *[]T, from []T) {
+	if *in != nil || len(from) == 0 {
+		return
+	}
+	*in = from
+	t.changed = true
+}
+
+// Match pairs each desired element with a distinct observed element that
+// matches it, so that lists the Settings 2.0 API treats as sets can be
+// late-initialized even if the API returns them in a different order. The
+// observed element at the same index is preferred. Desired elements without
+// a match are paired with the zero value of O.
+func Match[I, O any](in []I, observed []O, matches func(in *I, o O) bool) []O {
+	out := make([]O, len(in))
+	used := make([]bool, len(observed))
+
+	pair := func(i, j int) bool {
+		if used[j] || !matches(&in[i], observed[j]) {
+			return false
+		}
+		out[i], used[j] = observed[j], true
+		return true
+	}
+
+	paired := make([]bool, len(in))
+	for i := range in {
+		if i < len(observed) {
+			paired[i] = pair(i, i)
+		}
+	}
+	for i := range in {
+		for j := 0; !paired[i] && j < len(observed); j++ {
+			paired[i] = pair(i, j)
+		}
+	}
+
+	return out
+}
+
+// Equal returns true if in is unset or equal to from.
+func Equal[T comparable](in, from *T) bool {
+	return in == nil || (from != nil && *in == *from)
+}
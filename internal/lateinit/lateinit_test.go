@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lateinit
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func to[T any](v T) *T { return &v }
+
+func TestPtr(t *testing.T) {
+	type args struct {
+		in   *bool
+		from *bool
+	}
+
+	type want struct {
+		out     *bool
+		changed bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unset": {
+			reason: "An unset parameter should be filled from the observed value.",
+			args:   args{from: to(true)},
+			want:   want{out: to(true), changed: true},
+		},
+		"Set": {
+			reason: "A set parameter should never be overwritten.",
+			args:   args{in: to(false), from: to(true)},
+			want:   want{out: to(false)},
+		},
+		"NothingObserved": {
+			reason: "An unset parameter should stay unset if nothing was observed.",
+			args:   args{},
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := &Tracker{}
+			Ptr(tr, &tc.args.in, tc.args.from)
+			if diff := cmp.Diff(tc.want.out, tc.args.in); diff != "" {
+				t.Errorf("\n%s\nPtr(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changed, tr.Changed()); diff != "" {
+				t.Errorf("\n%s\nPtr(...): -want changed, +got changed:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	type myString string
+
+	type args struct {
+		in   *string
+		from myString
+	}
+
+	type want struct {
+		out     *string
+		changed bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unset": {
+			reason: "An unset parameter should be filled from the observed value.",
+			args:   args{from: "HOST"},
+			want:   want{out: to("HOST"), changed: true},
+		},
+		"Set": {
+			reason: "A set parameter should never be overwritten.",
+			args:   args{in: to("SERVICE"), from: "HOST"},
+			want:   want{out: to("SERVICE")},
+		},
+		"Empty": {
+			reason: "An unset parameter should stay unset if the observed value is empty.",
+			args:   args{},
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := &Tracker{}
+			String(tr, &tc.args.in, tc.args.from)
+			if diff := cmp.Diff(tc.want.out, tc.args.in); diff != "" {
+				t.Errorf("\n%s\nString(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changed, tr.Changed()); diff != "" {
+				t.Errorf("\n%s\nString(...): -want changed, +got changed:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	type item struct {
+		key string
+	}
+
+	matches := func(in *string, o *item) bool { return o != nil && o.key == *in }
+
+	a, b, c := &item{key: "a"}, &item{key: "b"}, &item{key: "c"}
+
+	type args struct {
+		in       []string
+		observed []*item
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []*item
+	}{
+		"SameOrder": {
+			reason: "Elements in the same order should be paired by index.",
+			args:   args{in: []string{"a", "b"}, observed: []*item{a, b}},
+			want:   []*item{a, b},
+		},
+		"Reordered": {
+			reason: "Reordered elements should be paired by content.",
+			args:   args{in: []string{"a", "b", "c"}, observed: []*item{c, a, b}},
+			want:   []*item{a, b, c},
+		},
+		"Unmatched": {
+			reason: "Desired elements without a match should be paired with the zero value.",
+			args:   args{in: []string{"a", "d"}, observed: []*item{b, a}},
+			want:   []*item{a, nil},
+		},
+		"Duplicates": {
+			reason: "Every observed element should be paired at most once.",
+			args:   args{in: []string{"a", "a"}, observed: []*item{a}},
+			want:   []*item{a, nil},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Match(tc.args.in, tc.args.observed, matches)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(item{})); diff != "" {
+				t.Errorf("\n%s\nMatch(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
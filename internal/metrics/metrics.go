@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics exposes Prometheus metrics of the calls the controllers make
+// to the Dynatrace API and of the drift they detect. The metrics are
+// registered with the controller-runtime registry, so they are served on the
+// metrics endpoint of the manager.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/credentials"
+)
+
+// Operations of a settings service.
+const (
+	OperationList         = "list"
+	OperationGet          = "get"
+	OperationCreate       = "create"
+	OperationUpdate       = "update"
+	OperationDelete       = "delete"
+	OperationValidate     = "validate"
+	OperationGetObject    = "get_object"
+	OperationUpdateObject = "update_object"
+)
+
+// Status codes recorded for calls that did not fail with an API error.
+const (
+	CodeSuccess = "2xx"
+	CodeError   = "error"
+)
+
+var (
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "dynatrace_api_requests_total",
+		Help: "Number of calls to the Dynatrace Settings API by tenant, kind, operation and status code.",
+	}, []string{"tenant", "kind", "operation", "code"})
+
+	latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "dynatrace_api_request_duration_seconds",
+		Help:    "Latency of calls to the Dynatrace Settings API by tenant, kind, operation and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"tenant", "kind", "operation", "code"})
+
+	throttled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "dynatrace_api_throttled_total",
+		Help: "Number of calls to the Dynatrace Settings API that failed because the tenant throttled them.",
+	}, []string{"tenant", "kind", "operation"})
+
+	drifted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "dynatrace_drift_detected_total",
+		Help: "Number of times a settings object was observed to have newly drifted from its desired state, by kind.",
+	}, []string{"kind"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(requests, latency, throttled, drifted)
+}
+
+// DriftDetected records that a settings object of the supplied kind newly
+// drifted from its desired state.
+func DriftDetected(kind string) {
+	drifted.WithLabelValues(kind).Inc()
+}
+
+// Instrument returns a function that creates the settings services of the
+// supplied kind like newService does, but records metrics of their calls. The
+// tenant is identified by the host of the environment URL of the credentials.
+func Instrument[T settings.Settings](kind string, newService func(creds []byte) (settings20.CRUDService[T], error)) func(creds []byte) (settings20.CRUDService[T], error) {
+	return func(creds []byte) (settings20.CRUDService[T], error) {
+		svc, err := newService(creds)
+		if err != nil {
+			return nil, err
+		}
+		return NewService(tenant(creds), kind, svc), nil
+	}
+}
+
+// NewService returns a settings service that records metrics of the calls it
+// delegates to the supplied service.
+func NewService[T settings.Settings](tenant, kind string, svc settings20.CRUDService[T]) settings20.CRUDService[T] {
+	return &service[T]{CRUDService: svc, tenant: tenant, kind: kind}
+}
+
+type service[T settings.Settings] struct {
+	settings20.CRUDService[T]
+
+	tenant string
+	kind   string
+}
+
+// WithContext returns a copy of the service that calls the service it
+// delegates to within the supplied context.
+func (s *service[T]) WithContext(ctx context.Context) settings20.CRUDService[T] {
+	return &service[T]{CRUDService: settings20.WithContext(ctx, s.CRUDService), tenant: s.tenant, kind: s.kind}
+}
+
+func (s *service[T]) List() (api.Stubs, error) {
+	start := time.Now()
+	stubs, err := s.CRUDService.List()
+	return stubs, s.record(OperationList, start, err)
+}
+
+func (s *service[T]) Get(id string, v T) error {
+	start := time.Now()
+	return s.record(OperationGet, start, s.CRUDService.Get(id, v))
+}
+
+func (s *service[T]) GetObject(id string, v T) (*settings20.Object, error) {
+	start := time.Now()
+	o, err := s.CRUDService.GetObject(id, v)
+	return o, s.record(OperationGetObject, start, err)
+}
+
+func (s *service[T]) Create(v T) (*api.Stub, error) {
+	start := time.Now()
+	stub, err := s.CRUDService.Create(v)
+	return stub, s.record(OperationCreate, start, err)
+}
+
+func (s *service[T]) Update(id string, v T) error {
+	start := time.Now()
+	return s.record(OperationUpdate, start, s.CRUDService.Update(id, v))
+}
+
+func (s *service[T]) UpdateObject(id string, v T, updateToken string) error {
+	start := time.Now()
+	return s.record(OperationUpdateObject, start, s.CRUDService.UpdateObject(id, v, updateToken))
+}
+
+func (s *service[T]) Delete(id string) error {
+	start := time.Now()
+	return s.record(OperationDelete, start, s.CRUDService.Delete(id))
+}
+
+func (s *service[T]) Validate(id string, v T) error {
+	start := time.Now()
+	return s.record(OperationValidate, start, s.CRUDService.Validate(id, v))
+}
+
+// record counts a call started at the supplied time that returned the
+// supplied error, records its latency and returns the error.
+func (s *service[T]) record(operation string, start time.Time, err error) error {
+	c := Code(err)
+	requests.WithLabelValues(s.tenant, s.kind, operation, c).Inc()
+	latency.WithLabelValues(s.tenant, s.kind, operation, c).Observe(time.Since(start).Seconds())
+	if c == strconv.Itoa(http.StatusTooManyRequests) {
+		throttled.WithLabelValues(s.tenant, s.kind, operation).Inc()
+	}
+	return err
+}
+
+// Code returns the status code label of a call that returned the supplied
+// error: the status code of an API error, CodeSuccess if there was no error
+// and CodeError if the API could not be reached.
+func Code(err error) string {
+	if err == nil {
+		return CodeSuccess
+	}
+	var re rest.Error
+	if errors.As(err, &re) && re.Code != 0 {
+		return strconv.Itoa(re.Code)
+	}
+	return CodeError
+}
+
+// tenant returns the host of the environment URL of the supplied credentials.
+func tenant(creds []byte) string {
+	c, err := credentials.Unmarshal(creds)
+	if err != nil {
+		return ""
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Host == "" {
+		return c.URL
+	}
+	return u.Host
+}
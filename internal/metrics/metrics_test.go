@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging"
+	autotaggingSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging/settings"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/rest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20/fake"
+	"github.com/crossplane/provider-dynatrace/internal/credentials"
+)
+
+func TestCode(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   string
+	}{
+		"Success": {
+			reason: "Calls without an error should be recorded as successful.",
+			want:   CodeSuccess,
+		},
+		"APIError": {
+			reason: "Calls failing with an API error should be recorded with its status code.",
+			err:    errors.Wrap(rest.Error{Code: http.StatusTooManyRequests}, "boom"),
+			want:   "429",
+		},
+		"OtherError": {
+			reason: "Calls that failed before the API responded should be recorded as errors.",
+			err:    errors.New("connection refused"),
+			want:   CodeError,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Code(tc.err)); diff != "" {
+				t.Errorf("\n%s\nCode(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func newService(data []byte) (settings20.CRUDService[*autotaggingSettings.Settings], error) {
+	c, err := credentials.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return settings20.NewService[*autotaggingSettings.Settings](autotagging.SchemaID, settings20.NewClient(c)), nil
+}
+
+func TestInstrument(t *testing.T) {
+	api := fake.NewServer()
+	defer api.Close()
+
+	id := api.Create(autotagging.SchemaID, fake.ScopeEnvironment, &autotaggingSettings.Settings{Name: "owner", Rules: autotaggingSettings.Rules{}})
+	api.Inject(fake.Fault{Method: http.MethodGet, Path: "/api/v2/settings/objects/" + id, Code: http.StatusTooManyRequests})
+
+	const kind = "AutoTag.tags.dynatrace.crossplane.io"
+	svc, err := Instrument(kind, newService)(api.Credentials())
+	if err != nil {
+		t.Fatalf("Instrument(...)(...): %v", err)
+	}
+
+	// Throttled, found and not found.
+	for _, id := range []string{id, id, "missing"} {
+		_, _ = svc.GetObject(id, &autotaggingSettings.Settings{})
+	}
+
+	u, _ := url.Parse(api.URL)
+	tenant := u.Host
+
+	want := map[string]float64{
+		"429":       1,
+		CodeSuccess: 1,
+		"404":       1,
+	}
+	for code, n := range want {
+		if got := testutil.ToFloat64(requests.WithLabelValues(tenant, kind, OperationGetObject, code)); got != n {
+			t.Errorf("requests{code=%q}: want %v, got %v", code, n, got)
+		}
+	}
+	if got := testutil.ToFloat64(throttled.WithLabelValues(tenant, kind, OperationGetObject)); got != 1 {
+		t.Errorf("throttled: want 1, got %v", got)
+	}
+	if got := testutil.CollectAndCount(latency, "dynatrace_api_request_duration_seconds"); got != len(want) {
+		t.Errorf("latency: want %d series, got %d", len(want), got)
+	}
+}
+
+func TestDriftDetected(t *testing.T) {
+	const kind = "Profile.alerting.dynatrace.crossplane.io"
+	DriftDetected(kind)
+	DriftDetected(kind)
+	if got := testutil.ToFloat64(drifted.WithLabelValues(kind)); got != 2 {
+		t.Errorf("drifted: want 2, got %v", got)
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plan previews the changes the controllers would make to the
+// settings objects of a tenant to reconcile a set of managed resource
+// manifests, without making them.
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+)
+
+const (
+	errDecode         = "cannot decode manifest"
+	errFmtConvert     = "cannot convert %s %s"
+	errFmtGet         = "cannot get %s"
+	errFmtUpdate      = "cannot record the observed state of %s"
+	errResolve        = "cannot resolve references"
+	errFmtWrite       = "cannot write plan of %s"
+	errWriteSummary   = "cannot write summary of plan"
+	errFmtNotManaged  = "%s is not a managed resource"
+	errFmtUnknownKind = "kind %s is not managed by this provider"
+)
+
+// A Resource is a managed resource and the change planned for it.
+type Resource struct {
+	generic.Change
+
+	// GroupKind and Name of the managed resource.
+	GroupKind string
+	Name      string
+
+	// Err is set if no change could be planned, e.g. because the desired
+	// state is invalid or references cannot be resolved.
+	Err error
+}
+
+// A Summary counts the planned changes by action.
+type Summary struct {
+	Create, Update, Delete, Unchanged, Failed int
+}
+
+// Summarize the supplied planned changes.
+func Summarize(rs []Resource) Summary {
+	s := Summary{}
+	for _, r := range rs {
+		switch {
+		case r.Err != nil:
+			s.Failed++
+		case r.Action == generic.ActionCreate:
+			s.Create++
+		case r.Action == generic.ActionUpdate:
+			s.Update++
+		case r.Action == generic.ActionDelete:
+			s.Delete++
+		default:
+			s.Unchanged++
+		}
+	}
+	return s
+}
+
+// Read the managed resources of the supplied scheme from a multi-document
+// YAML or JSON stream. Other objects, such as ProviderConfigs, are skipped.
+func Read(r io.Reader, s *runtime.Scheme) ([]resource.Managed, error) {
+	d := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	var out []resource.Managed
+	for {
+		u := &unstructured.Unstructured{}
+		err := d.Decode(&u.Object)
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errDecode)
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+
+		gvk := u.GroupVersionKind()
+		if !s.Recognizes(gvk) {
+			continue
+		}
+		obj, err := s.New(gvk)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtConvert, gvk.Kind, u.GetName())
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+			return nil, errors.Wrapf(err, errFmtConvert, gvk.Kind, u.GetName())
+		}
+		mg, ok := obj.(resource.Managed)
+		if !ok {
+			continue
+		}
+		mg.GetObjectKind().SetGroupVersionKind(gvk)
+		out = append(out, mg)
+	}
+}
+
+// A resolver resolves the references of a managed resource.
+type resolver interface {
+	ResolveReferences(ctx context.Context, c client.Reader) error
+}
+
+// Plan the changes the controllers would make to reconcile the desired managed
+// resources and to delete the deleted ones, using the supplied credentials.
+// Kinds are planned in the order of the supplied planners. References are
+// resolved among the desired managed resources, using the state the planners
+// observed for them, like the controllers resolve them.
+func Plan(ctx context.Context, s *runtime.Scheme, creds []byte, planners []generic.Planner, desired, deleted []resource.Managed) ([]Resource, error) {
+	objs := make([]client.Object, len(desired))
+	for i, mg := range desired {
+		objs[i] = mg.DeepCopyObject().(client.Object)
+	}
+	kube := kfake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+
+	out := make([]Resource, 0, len(desired)+len(deleted))
+	planned := map[resource.Managed]bool{}
+	for _, p := range planners {
+		for _, mg := range desired {
+			if groupKind(mg) != p.GroupKind() {
+				continue
+			}
+			planned[mg] = true
+			r, err := planDesired(ctx, kube, creds, p, mg)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, r)
+		}
+		for _, mg := range deleted {
+			if groupKind(mg) != p.GroupKind() {
+				continue
+			}
+			planned[mg] = true
+			r := Resource{GroupKind: groupKind(mg), Name: mg.GetName()}
+			r.Change, r.Err = p.Plan(ctx, creds, mg.DeepCopyObject().(resource.Managed), true)
+			out = append(out, r)
+		}
+	}
+
+	for _, mg := range append(append([]resource.Managed{}, desired...), deleted...) {
+		if !planned[mg] {
+			out = append(out, Resource{GroupKind: groupKind(mg), Name: mg.GetName(), Err: errors.Errorf(errFmtUnknownKind, groupKind(mg))})
+		}
+	}
+
+	return out, nil
+}
+
+// planDesired plans the change to the supplied desired managed resource and
+// records its observed state in the supplied client, so that references to it
+// resolve.
+func planDesired(ctx context.Context, kube client.Client, creds []byte, p generic.Planner, mg resource.Managed) (Resource, error) {
+	r := Resource{GroupKind: groupKind(mg), Name: mg.GetName()}
+
+	cr, ok := mg.DeepCopyObject().(resource.Managed)
+	if !ok {
+		return r, errors.Errorf(errFmtNotManaged, mg.GetName())
+	}
+	if err := kube.Get(ctx, types.NamespacedName{Name: mg.GetName()}, cr); err != nil {
+		return r, errors.Wrapf(err, errFmtGet, mg.GetName())
+	}
+
+	if rr, ok := cr.(resolver); ok {
+		if err := rr.ResolveReferences(ctx, kube); err != nil {
+			r.Err = errors.Wrap(err, errResolve)
+			return r, nil
+		}
+	}
+
+	r.Change, r.Err = p.Plan(ctx, creds, cr, false)
+
+	if err := kube.Update(ctx, cr); resource.Ignore(kerrors.IsNotFound, err) != nil {
+		return r, errors.Wrapf(err, errFmtUpdate, mg.GetName())
+	}
+	return r, nil
+}
+
+func groupKind(mg resource.Managed) string {
+	gvk := mg.GetObjectKind().GroupVersionKind()
+	return schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}.String()
+}
+
+// Write the supplied planned changes in a human readable form, followed by a
+// summary.
+func Write(w io.Writer, rs []Resource) error {
+	for _, r := range rs {
+		if _, err := io.WriteString(w, format(r)); err != nil {
+			return errors.Wrapf(err, errFmtWrite, r.Name)
+		}
+	}
+	s := Summarize(rs)
+	_, err := fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to delete, %d unchanged, %d failed.\n", s.Create, s.Update, s.Delete, s.Unchanged, s.Failed)
+	return errors.Wrap(err, errWriteSummary)
+}
+
+func format(r Resource) string {
+	name := r.GroupKind + "/" + r.Name
+	if r.ID != "" {
+		name += " (" + r.ID + ")"
+	}
+
+	b := &strings.Builder{}
+	switch {
+	case r.Err != nil:
+		fmt.Fprintf(b, "! %s cannot be planned: %s\n", name, r.Err)
+	case r.Action == generic.ActionCreate:
+		fmt.Fprintf(b, "+ %s will be created\n", name)
+	case r.Action == generic.ActionUpdate:
+		fmt.Fprintf(b, "~ %s will be updated in: %s\n", name, strings.Join(r.Drift, ", "))
+	case r.Action == generic.ActionDelete:
+		fmt.Fprintf(b, "- %s will be deleted\n", name)
+	case len(r.Drift) > 0:
+		fmt.Fprintf(b, "= %s is unchanged: %s; it drifted in: %s\n", name, r.Reason, strings.Join(r.Drift, ", "))
+	default:
+		fmt.Fprintf(b, "= %s is unchanged: %s\n", name, r.Reason)
+	}
+	if r.Err == nil && r.Diff != "" {
+		for _, l := range strings.Split(strings.TrimRight(r.Diff, "\n"), "\n") {
+			fmt.Fprintf(b, "    %s\n", l)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile"
+	profileSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile/settings"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
+	emailSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications/email/settings"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging"
+	autotaggingSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging/settings"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/provider-dynatrace/apis"
+	alertingv1alpha1 "github.com/crossplane/provider-dynatrace/apis/alerting/v1alpha1"
+	notificationv1alpha1 "github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
+	tagsv1alpha1 "github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20/fake"
+	dynatrace "github.com/crossplane/provider-dynatrace/internal/controller"
+	"github.com/crossplane/provider-dynatrace/internal/controller/generic"
+)
+
+// summary is the part of a planned change these tests check.
+type summary struct {
+	GroupKind string
+	Name      string
+	Action    generic.Action
+	ID        string
+	Drift     []string
+	Failed    bool
+}
+
+func scheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatalf("apis.AddToScheme(...): %v", err)
+	}
+	return s
+}
+
+func read(t *testing.T, s *runtime.Scheme, manifests string) []resource.Managed {
+	t.Helper()
+	mgs, err := Read(strings.NewReader(manifests), s)
+	if err != nil {
+		t.Fatalf("Read(...): %v", err)
+	}
+	return mgs
+}
+
+func TestPlan(t *testing.T) {
+	api := fake.NewServer()
+	defer api.Close()
+
+	prod := api.Create(profile.SchemaID, fake.ScopeEnvironment, &profileSettings.Profile{Name: "prod-renamed"})
+	old := api.Create(profile.SchemaID, fake.ScopeEnvironment, &profileSettings.Profile{Name: "old"})
+	shared := api.Create(profile.SchemaID, fake.ScopeEnvironment, &profileSettings.Profile{Name: "shared"})
+	email := api.Create(notifications.SchemaID, fake.ScopeEnvironment, &notifications.Notification{
+		Type:      notifications.Types.Email,
+		Enabled:   true,
+		Name:      "ops",
+		ProfileID: prod,
+		Email:     &emailSettings.Email{Subject: "Problem", Body: "{ProblemDetailsHTML}", Recipients: []string{"ops@example.org"}},
+	})
+	api.Create(notifications.SchemaID, fake.ScopeEnvironment, &notifications.Notification{
+		Type:      notifications.Types.Email,
+		Enabled:   true,
+		Name:      "unmanaged",
+		ProfileID: shared,
+		Email:     &emailSettings.Email{Subject: "Problem", Body: "{ProblemDetailsHTML}", Recipients: []string{"ops@example.org"}},
+	})
+
+	owner := api.Create(autotagging.SchemaID, fake.ScopeEnvironment, &autotaggingSettings.Settings{Name: "Owner", Rules: autotaggingSettings.Rules{}})
+
+	s := scheme(t)
+	desired := read(t, s, `
+apiVersion: dynatrace.crossplane.io/v1alpha1
+kind: ProviderConfig
+metadata:
+  name: default
+spec:
+  credentials:
+    source: None
+---
+apiVersion: alerting.dynatrace.crossplane.io/v1alpha1
+kind: Profile
+metadata:
+  name: prod
+  annotations:
+    crossplane.io/external-name: `+prod+`
+spec:
+  forProvider:
+    name: prod
+---
+apiVersion: alerting.dynatrace.crossplane.io/v1alpha1
+kind: Profile
+metadata:
+  name: new
+spec:
+  forProvider:
+    name: new
+---
+apiVersion: alerting.dynatrace.crossplane.io/v1alpha1
+kind: Profile
+metadata:
+  name: tagged
+spec:
+  forProvider:
+    name: tagged
+    severityRules:
+    - severityLevel: AVAILABILITY
+      tagFilterIncludeMode: INCLUDE_ANY
+      tagFilterRefs:
+      - name: owner
+        value: team-a
+---
+apiVersion: tags.dynatrace.crossplane.io/v1alpha1
+kind: AutoTag
+metadata:
+  name: owner
+  annotations:
+    crossplane.io/external-name: `+owner+`
+spec:
+  forProvider:
+    name: Owner
+---
+apiVersion: notification.dynatrace.crossplane.io/v1alpha1
+kind: Email
+metadata:
+  name: ops
+  annotations:
+    crossplane.io/external-name: `+email+`
+spec:
+  forProvider:
+    enabled: true
+    name: ops
+    subject: Problem
+    body: "{ProblemDetailsHTML}"
+    to: [ops@example.org]
+    alertingProfileRef:
+      name: prod
+---
+apiVersion: notification.dynatrace.crossplane.io/v1alpha1
+kind: Email
+metadata:
+  name: ops-new
+spec:
+  forProvider:
+    enabled: true
+    name: ops-new
+    subject: Problem
+    body: "{ProblemDetailsHTML}"
+    to: [ops@example.org]
+    alertingProfileRef:
+      name: new
+`)
+	deleted := read(t, s, `
+apiVersion: alerting.dynatrace.crossplane.io/v1alpha1
+kind: Profile
+metadata:
+  name: old
+  annotations:
+    crossplane.io/external-name: `+old+`
+spec:
+  forProvider:
+    name: old
+---
+apiVersion: alerting.dynatrace.crossplane.io/v1alpha1
+kind: Profile
+metadata:
+  name: shared
+  annotations:
+    crossplane.io/external-name: `+shared+`
+spec:
+  forProvider:
+    name: shared
+---
+apiVersion: tags.dynatrace.crossplane.io/v1alpha1
+kind: AutoTag
+metadata:
+  name: gone
+  annotations:
+    crossplane.io/external-name: missing
+spec:
+  forProvider:
+    name: gone
+`)
+
+	before := len(api.Objects(profile.SchemaID)) + len(api.Objects(notifications.SchemaID))
+	observed, _ := api.Object(prod)
+
+	rs, err := Plan(context.Background(), s, api.Credentials(), dynatrace.Planners(), desired, deleted)
+	if err != nil {
+		t.Fatalf("Plan(...): %v", err)
+	}
+
+	want := []summary{
+		{GroupKind: tagsv1alpha1.AutoTagGroupKind, Name: "owner", Action: generic.ActionNone, ID: owner},
+		{GroupKind: tagsv1alpha1.AutoTagGroupKind, Name: "gone", Action: generic.ActionNone},
+		{GroupKind: alertingv1alpha1.ProfileGroupKind, Name: "prod", Action: generic.ActionUpdate, ID: prod, Drift: []string{"name"}},
+		{GroupKind: alertingv1alpha1.ProfileGroupKind, Name: "new", Action: generic.ActionCreate, Drift: []string{"name"}},
+		// The tag reference resolves to the observed name of the AutoTag,
+		// which is planned first.
+		{GroupKind: alertingv1alpha1.ProfileGroupKind, Name: "tagged", Action: generic.ActionCreate, Drift: []string{"name", "severityRules"}},
+		{GroupKind: alertingv1alpha1.ProfileGroupKind, Name: "old", Action: generic.ActionDelete, ID: old},
+		// An unmanaged notification still sends the problems of the shared
+		// profile.
+		{GroupKind: alertingv1alpha1.ProfileGroupKind, Name: "shared", Failed: true},
+		{GroupKind: notificationv1alpha1.EmailGroupKind, Name: "ops", Action: generic.ActionNone, ID: email},
+		// The new profile has no ID to resolve to until it was created.
+		{GroupKind: notificationv1alpha1.EmailGroupKind, Name: "ops-new", Failed: true},
+	}
+	got := make([]summary, len(rs))
+	for i, r := range rs {
+		got[i] = summary{GroupKind: r.GroupKind, Name: r.Name, Action: r.Action, ID: r.ID, Drift: r.Drift, Failed: r.Err != nil}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Plan(...): -want, +got:\n%s\n", diff)
+	}
+
+	for _, r := range rs {
+		if r.Name == "tagged" && !strings.Contains(r.Diff, "Owner:team-a") {
+			t.Errorf("Plan(...): want tag filter Owner:team-a in diff of profile tagged, got:\n%s", r.Diff)
+		}
+	}
+
+	if after := len(api.Objects(profile.SchemaID)) + len(api.Objects(notifications.SchemaID)); after != before {
+		t.Errorf("Plan(...): want %d settings objects, got %d", before, after)
+	}
+	if o, _ := api.Object(prod); o.UpdateToken != observed.UpdateToken {
+		t.Errorf("Plan(...): profile %s was updated", prod)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rs := []Resource{
+		{GroupKind: "Profile.alerting.dynatrace.crossplane.io", Name: "new", Change: generic.Change{Action: generic.ActionCreate, Diff: "+ name\n"}},
+		{GroupKind: "Profile.alerting.dynatrace.crossplane.io", Name: "prod", Change: generic.Change{Action: generic.ActionUpdate, ID: "object-1", Drift: []string{"name"}, Diff: "- old\n+ new"}},
+		{GroupKind: "Profile.alerting.dynatrace.crossplane.io", Name: "old", Change: generic.Change{Action: generic.ActionDelete, ID: "object-2"}},
+		{GroupKind: "AutoTag.tags.dynatrace.crossplane.io", Name: "observed", Change: generic.Change{Action: generic.ActionNone, ID: "object-3", Drift: []string{"rules"}, Reason: "not allowed"}},
+		{GroupKind: "Email.notification.dynatrace.crossplane.io", Name: "ops", Err: errors.New("boom")},
+	}
+
+	want := `+ Profile.alerting.dynatrace.crossplane.io/new will be created
+    + name
+~ Profile.alerting.dynatrace.crossplane.io/prod (object-1) will be updated in: name
+    - old
+    + new
+- Profile.alerting.dynatrace.crossplane.io/old (object-2) will be deleted
+= AutoTag.tags.dynatrace.crossplane.io/observed (object-3) is unchanged: not allowed; it drifted in: rules
+! Email.notification.dynatrace.crossplane.io/ops cannot be planned: boom
+Plan: 1 to create, 1 to update, 1 to delete, 1 unchanged, 1 failed.
+`
+
+	b := &bytes.Buffer{}
+	if err := Write(b, rs); err != nil {
+		t.Fatalf("Write(...): %v", err)
+	}
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("Write(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+const (
+	serviceName = "provider-dynatrace"
+
+	errNewExporter = "cannot create OTLP trace exporter"
+	errNewResource = "cannot describe the traced service"
+)
+
+// Options configure the export of traces.
+type Options struct {
+	// Endpoint of the OTLP/HTTP collector traces are exported to, as
+	// host:port. Traces are not exported if it is empty.
+	Endpoint string
+
+	// Insecure exports traces over HTTP rather than HTTPS.
+	Insecure bool
+
+	// SampleRatio is the fraction of reconciles that are traced, unless the
+	// parent of their span was sampled.
+	SampleRatio float64
+}
+
+// Setup makes the global tracer provider export spans as configured by the
+// supplied options. It returns a function that flushes the spans that were
+// not exported yet, which should be called before the process exits.
+func Setup(ctx context.Context, o Options) (func(context.Context) error, error) {
+	if o.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	eo := []otlptracehttp.Option{otlptracehttp.WithEndpoint(o.Endpoint)}
+	if o.Insecure {
+		eo = append(eo, otlptracehttp.WithInsecure())
+	}
+	exp, err := otlptracehttp.New(ctx, eo...)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewExporter)
+	}
+
+	r, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, errors.Wrap(err, errNewResource)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(r),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing traces the reconciles of the controllers and the calls they
+// make to the Dynatrace API with OpenTelemetry. Spans are recorded by the
+// global tracer provider, which drops them unless Setup configured an OTLP
+// exporter.
+package tracing
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/settings"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+)
+
+const instrumentation = "github.com/crossplane/provider-dynatrace"
+
+// Attributes of the recorded spans.
+const (
+	AttributeKind           = attribute.Key("crossplane.kind")
+	AttributeName           = attribute.Key("crossplane.name")
+	AttributeProviderConfig = attribute.Key("crossplane.providerconfig")
+	AttributeObjectID       = settings20.AttributeObjectID
+)
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// NewReconciler returns a reconciler that records a span of every reconcile
+// of a managed resource of the supplied kind by the supplied reconciler. The
+// spans of the operations of the reconcile are its children. Time spent with
+// the Kubernetes API is part of the reconcile span, but not of any child.
+func NewReconciler(kind string, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		ctx, span := tracer().Start(ctx, "Reconcile", trace.WithAttributes(AttributeKind.String(kind), AttributeName.String(req.Name)))
+		res, err := r.Reconcile(ctx, req)
+		fail(span, err)
+		span.End()
+		return res, err
+	})
+}
+
+// NewReferenceResolver returns a reference resolver that records a span of
+// every resolution of the references of a managed resource of the supplied
+// kind by the supplied resolver.
+func NewReferenceResolver(kind string, rr managed.ReferenceResolver) managed.ReferenceResolver {
+	return managed.ReferenceResolverFn(func(ctx context.Context, mg resource.Managed) error {
+		ctx, span := Start(ctx, "ResolveReferences", kind, mg)
+		err := rr.ResolveReferences(ctx, mg)
+		End(span, mg, err)
+		return err
+	})
+}
+
+// Start a span of an operation on the supplied managed resource of the
+// supplied kind.
+func Start(ctx context.Context, operation, kind string, mg resource.Managed) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{AttributeKind.String(kind), AttributeName.String(mg.GetName())}
+	if ref := mg.GetProviderConfigReference(); ref != nil {
+		attrs = append(attrs, AttributeProviderConfig.String(ref.Name))
+	}
+	if id := meta.GetExternalName(mg); id != "" {
+		attrs = append(attrs, AttributeObjectID.String(id))
+	}
+	return tracer().Start(ctx, operation, trace.WithAttributes(attrs...))
+}
+
+// End the supplied span of an operation on the supplied managed resource that
+// returned the supplied error. The ID of the settings object is recorded
+// again, as the operation may have created it.
+func End(span trace.Span, mg resource.Managed, err error) {
+	if id := meta.GetExternalName(mg); id != "" {
+		span.SetAttributes(AttributeObjectID.String(id))
+	}
+	fail(span, err)
+	span.End()
+}
+
+func fail(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// NewService returns a settings service that records a client span within
+// the supplied context for every call it delegates to the supplied service.
+// The HTTP requests of a call, e.g. one per page of a list, are recorded as
+// children of its span by the settings20 Client.
+func NewService[T settings.Settings](ctx context.Context, svc settings20.CRUDService[T]) settings20.CRUDService[T] {
+	return &service[T]{CRUDService: svc, ctx: ctx}
+}
+
+type service[T settings.Settings] struct {
+	settings20.CRUDService[T]
+
+	ctx context.Context
+}
+
+func (s *service[T]) List() (api.Stubs, error) {
+	svc, span := s.start("List", "")
+	stubs, err := svc.List()
+	return stubs, s.end(span, err)
+}
+
+func (s *service[T]) Get(id string, v T) error {
+	svc, span := s.start("Get", id)
+	return s.end(span, svc.Get(id, v))
+}
+
+func (s *service[T]) GetObject(id string, v T) (*settings20.Object, error) {
+	svc, span := s.start("GetObject", id)
+	o, err := svc.GetObject(id, v)
+	return o, s.end(span, err)
+}
+
+func (s *service[T]) Create(v T) (*api.Stub, error) {
+	svc, span := s.start("Create", "")
+	stub, err := svc.Create(v)
+	if stub != nil {
+		span.SetAttributes(AttributeObjectID.String(stub.ID))
+	}
+	return stub, s.end(span, err)
+}
+
+func (s *service[T]) Update(id string, v T) error {
+	svc, span := s.start("Update", id)
+	return s.end(span, svc.Update(id, v))
+}
+
+func (s *service[T]) UpdateObject(id string, v T, updateToken string) error {
+	svc, span := s.start("UpdateObject", id)
+	return s.end(span, svc.UpdateObject(id, v, updateToken))
+}
+
+func (s *service[T]) Delete(id string) error {
+	svc, span := s.start("Delete", id)
+	return s.end(span, svc.Delete(id))
+}
+
+func (s *service[T]) Validate(id string, v T) error {
+	svc, span := s.start("Validate", id)
+	return s.end(span, svc.Validate(id, v))
+}
+
+// start a span of a call and return the service to make it with, which sends
+// its requests within the span.
+func (s *service[T]) start(operation, id string) (settings20.CRUDService[T], trace.Span) {
+	ctx, span := tracer().Start(s.ctx, "Settings API "+operation, trace.WithSpanKind(trace.SpanKindClient))
+	if id != "" {
+		span.SetAttributes(AttributeObjectID.String(id))
+	}
+	return settings20.WithContext(ctx, s.CRUDService), span
+}
+
+func (s *service[T]) end(span trace.Span, err error) error {
+	fail(span, err)
+	span.End()
+	return err
+}
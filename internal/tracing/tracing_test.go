@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging"
+	autotaggingSettings "github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging/settings"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20/fake"
+	"github.com/crossplane/provider-dynatrace/internal/credentials"
+)
+
+// span is the part of a recorded span these tests check.
+type span struct {
+	Name       string
+	Kind       trace.SpanKind
+	Parent     string
+	Attributes map[attribute.Key]string
+	Failed     bool
+}
+
+// attributes are the attributes of a recorded span these tests check.
+var attributes = map[attribute.Key]bool{
+	AttributeKind:           true,
+	AttributeName:           true,
+	AttributeProviderConfig: true,
+	AttributeObjectID:       true,
+}
+
+func TestTrace(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+	api := fake.NewServer()
+	defer api.Close()
+	id := api.Create(autotagging.SchemaID, fake.ScopeEnvironment, &autotaggingSettings.Settings{Name: "owner", Rules: autotaggingSettings.Rules{}})
+
+	c, err := credentials.Unmarshal(api.Credentials())
+	if err != nil {
+		t.Fatalf("credentials.Unmarshal(...): %v", err)
+	}
+	base := settings20.NewService[*autotaggingSettings.Settings](autotagging.SchemaID, settings20.NewClient(c))
+
+	cr := &v1alpha1.AutoTag{ObjectMeta: metav1.ObjectMeta{Name: "owner"}}
+	cr.SetProviderConfigReference(&xpv1.Reference{Name: "tenant"})
+
+	rr := NewReferenceResolver(v1alpha1.AutoTagGroupKind, managed.ReferenceResolverFn(func(context.Context, resource.Managed) error { return nil }))
+	r := NewReconciler(v1alpha1.AutoTagGroupKind, reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		if err := rr.ResolveReferences(ctx, cr); err != nil {
+			return reconcile.Result{}, err
+		}
+		ctx, s := Start(ctx, "Observe", v1alpha1.AutoTagGroupKind, cr)
+		svc := NewService(ctx, base)
+		_, _ = svc.List()
+		_, _ = svc.GetObject(id, &autotaggingSettings.Settings{})
+		_, err := svc.GetObject("missing", &autotaggingSettings.Settings{})
+		meta.SetExternalName(cr, id)
+		err = errors.Wrap(err, "cannot observe")
+		End(s, cr, err)
+		return reconcile.Result{}, err
+	}))
+	_, _ = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "owner"}})
+
+	want := []span{
+		{
+			Name:   "ResolveReferences",
+			Kind:   trace.SpanKindInternal,
+			Parent: "Reconcile",
+			Attributes: map[attribute.Key]string{
+				AttributeKind:           v1alpha1.AutoTagGroupKind,
+				AttributeName:           "owner",
+				AttributeProviderConfig: "tenant",
+			},
+		},
+		{
+			Name:       "HTTP GET",
+			Kind:       trace.SpanKindClient,
+			Parent:     "Settings API List",
+			Attributes: map[attribute.Key]string{},
+		},
+		{
+			Name:       "Settings API List",
+			Kind:       trace.SpanKindClient,
+			Parent:     "Observe",
+			Attributes: map[attribute.Key]string{},
+		},
+		{
+			Name:       "HTTP GET",
+			Kind:       trace.SpanKindClient,
+			Parent:     "Settings API GetObject",
+			Attributes: map[attribute.Key]string{AttributeObjectID: id},
+		},
+		{
+			Name:       "Settings API GetObject",
+			Kind:       trace.SpanKindClient,
+			Parent:     "Observe",
+			Attributes: map[attribute.Key]string{AttributeObjectID: id},
+		},
+		{
+			Name:       "HTTP GET",
+			Kind:       trace.SpanKindClient,
+			Parent:     "Settings API GetObject",
+			Attributes: map[attribute.Key]string{AttributeObjectID: "missing"},
+			Failed:     true,
+		},
+		{
+			Name:       "Settings API GetObject",
+			Kind:       trace.SpanKindClient,
+			Parent:     "Observe",
+			Attributes: map[attribute.Key]string{AttributeObjectID: "missing"},
+			Failed:     true,
+		},
+		{
+			Name:   "Observe",
+			Kind:   trace.SpanKindInternal,
+			Parent: "Reconcile",
+			Attributes: map[attribute.Key]string{
+				AttributeKind:           v1alpha1.AutoTagGroupKind,
+				AttributeName:           "owner",
+				AttributeProviderConfig: "tenant",
+				AttributeObjectID:       id,
+			},
+			Failed: true,
+		},
+		{
+			Name: "Reconcile",
+			Kind: trace.SpanKindInternal,
+			Attributes: map[attribute.Key]string{
+				AttributeKind: v1alpha1.AutoTagGroupKind,
+				AttributeName: "owner",
+			},
+			Failed: true,
+		},
+	}
+
+	ended := sr.Ended()
+	names := map[trace.SpanID]string{}
+	for _, s := range ended {
+		names[s.SpanContext().SpanID()] = s.Name()
+	}
+	got := make([]span, len(ended))
+	for i, s := range ended {
+		got[i] = span{
+			Name:       s.Name(),
+			Kind:       s.SpanKind(),
+			Parent:     names[s.Parent().SpanID()],
+			Attributes: map[attribute.Key]string{},
+			Failed:     s.Status().Code == codes.Error,
+		}
+		for _, a := range s.Attributes() {
+			// The HTTP attributes recorded by otelhttp are not ours to test.
+			if _, ok := attributes[a.Key]; ok {
+				got[i].Attributes[a.Key] = a.Value.AsString()
+			}
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("-want spans, +got spans:\n%s\n", diff)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Setup(...): %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown(...): %v", err)
+	}
+}
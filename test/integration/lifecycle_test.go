@@ -0,0 +1,258 @@
+//go:build integration
+
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/alerting/profile"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/problem/notifications"
+	"github.com/dynatrace-oss/terraform-provider-dynatrace/dynatrace/api/builtin/tags/autotagging"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	alertingv1alpha1 "github.com/crossplane/provider-dynatrace/apis/alerting/v1alpha1"
+	notificationv1alpha1 "github.com/crossplane/provider-dynatrace/apis/notification/v1alpha1"
+	tagsv1alpha1 "github.com/crossplane/provider-dynatrace/apis/tags/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+)
+
+// TestAlerting creates an alerting Profile and an Email notification that
+// references it, corrects drift of the Profile and deletes both, which requires
+// deleting the notification first.
+func TestAlerting(t *testing.T) {
+	p := &alertingv1alpha1.Profile{
+		ObjectMeta: metav1.ObjectMeta{Name: "integration-profile"},
+		Spec: alertingv1alpha1.ProfileSpec{ForProvider: alertingv1alpha1.ProfileParameters{
+			Name: "integration-profile",
+			SeverityRules: []alertingv1alpha1.SeverityRule{
+				{SeverityLevel: alertingv1alpha1.SeverityLevelAvailability, TagFilterIncludeMode: alertingv1alpha1.IncludeAny, Tags: []string{"env:prod"}},
+			},
+		}},
+	}
+	create(t, p)
+	eventually(t, "the Profile to become ready", func() bool { return ready(t, p) })
+	pid := meta.GetExternalName(p)
+	if name := valueName(t, pid); name != "integration-profile" {
+		t.Fatalf("profile %s: want name %q, got %q", pid, "integration-profile", name)
+	}
+
+	// Changes made outside of Crossplane are reverted on the next poll.
+	setValueName(t, pid, "renamed-profile")
+	eventually(t, "the renamed profile to be corrected", func() bool {
+		return valueName(t, pid) == "integration-profile"
+	})
+
+	e := &notificationv1alpha1.Email{
+		ObjectMeta: metav1.ObjectMeta{Name: "integration-email"},
+		Spec: notificationv1alpha1.EmailSpec{ForProvider: notificationv1alpha1.EmailParameters{
+			Enabled:            true,
+			Name:               "integration-email",
+			Subject:            "Problem",
+			Body:               "{ProblemDetailsHTML}",
+			To:                 []string{"ops@example.org"},
+			AlertingProfileRef: &xpv1.Reference{Name: p.GetName()},
+		}},
+	}
+	create(t, e)
+	eventually(t, "the Email to become ready", func() bool { return ready(t, e) })
+	if got := e.Spec.ForProvider.AlertingProfile; got == nil || *got != pid {
+		t.Errorf("email: want spec.forProvider.alertingProfile %q resolved from the Profile, got %v", pid, got)
+	}
+	var n struct {
+		ProfileID string `json:"alertingProfile"`
+	}
+	value(t, meta.GetExternalName(e), &n)
+	if n.ProfileID != pid {
+		t.Errorf("notification %s: want alerting profile %q, got %q", meta.GetExternalName(e), pid, n.ProfileID)
+	}
+
+	// The Profile is not deleted while the notification references it.
+	del(t, p)
+	eventually(t, "deletion of the Profile to be blocked", func() bool {
+		return get(t, p) && p.GetCondition(apisv1alpha1.TypeDeletionBlocked).Status == corev1.ConditionTrue
+	})
+	if _, ok := api.Object(pid); !ok {
+		t.Fatalf("profile %s was deleted while a notification references it", pid)
+	}
+
+	del(t, e)
+	eventually(t, "the Email to be deleted", func() bool { return !get(t, e) })
+	eventually(t, "the Profile to be deleted", func() bool { return !get(t, p) })
+	if got := api.Objects(notifications.SchemaID); len(got) != 0 {
+		t.Errorf("want no notifications, got %d", len(got))
+	}
+	if got := api.Objects(profile.SchemaID); len(got) != 0 {
+		t.Errorf("want no profiles, got %d", len(got))
+	}
+}
+
+// TestAutoTag creates an AutoTag, recreates its settings object after it was
+// deleted outside of Crossplane and deletes it.
+func TestAutoTag(t *testing.T) {
+	a := &tagsv1alpha1.AutoTag{
+		ObjectMeta: metav1.ObjectMeta{Name: "integration-tag"},
+		Spec:       tagsv1alpha1.AutoTagSpec{ForProvider: tagsv1alpha1.AutoTagParameters{Name: "integration-tag"}},
+	}
+	create(t, a)
+	eventually(t, "the AutoTag to become ready", func() bool { return ready(t, a) })
+	id := meta.GetExternalName(a)
+
+	if !api.Delete(id) {
+		t.Fatalf("auto-tag %s does not exist", id)
+	}
+	eventually(t, "the AutoTag to be recreated", func() bool {
+		return ready(t, a) && meta.GetExternalName(a) != id && len(api.Objects(autotagging.SchemaID)) == 1
+	})
+
+	del(t, a)
+	eventually(t, "the AutoTag to be deleted", func() bool { return !get(t, a) })
+	if got := api.Objects(autotagging.SchemaID); len(got) != 0 {
+		t.Errorf("want no auto-tags, got %d", len(got))
+	}
+}
+
+// TestTagReferences creates a Profile whose severity rule references an
+// AutoTag and renames the AutoTag, which updates the tag filter of the
+// profile.
+func TestTagReferences(t *testing.T) {
+	a := &tagsv1alpha1.AutoTag{
+		ObjectMeta: metav1.ObjectMeta{Name: "integration-owner"},
+		Spec:       tagsv1alpha1.AutoTagSpec{ForProvider: tagsv1alpha1.AutoTagParameters{Name: "owner"}},
+	}
+	team := "team-a"
+	p := &alertingv1alpha1.Profile{
+		ObjectMeta: metav1.ObjectMeta{Name: "integration-tagged-profile"},
+		Spec: alertingv1alpha1.ProfileSpec{ForProvider: alertingv1alpha1.ProfileParameters{
+			Name: "integration-tagged-profile",
+			SeverityRules: []alertingv1alpha1.SeverityRule{{
+				SeverityLevel:        alertingv1alpha1.SeverityLevelAvailability,
+				TagFilterIncludeMode: alertingv1alpha1.IncludeAny,
+				TagRefs:              []alertingv1alpha1.TagReference{{Name: a.GetName(), Value: &team}},
+			}},
+		}},
+	}
+	create(t, p)
+	create(t, a)
+	eventually(t, "the AutoTag to become ready", func() bool { return ready(t, a) })
+	eventually(t, "the Profile to become ready", func() bool { return ready(t, p) })
+	pid := meta.GetExternalName(p)
+	if got := tagFilter(t, pid); len(got) != 1 || got[0] != "owner:team-a" {
+		t.Errorf("profile %s: want tag filter [owner:team-a], got %v", pid, got)
+	}
+
+	if !get(t, a) {
+		t.Fatalf("AutoTag %s does not exist", a.GetName())
+	}
+	a.Spec.ForProvider.Name = "team-owner"
+	if err := kube.Update(context.Background(), a); err != nil {
+		t.Fatalf("Update(%s): %v", a.GetName(), err)
+	}
+	eventually(t, "the tag filter to follow the renamed AutoTag", func() bool {
+		got := tagFilter(t, pid)
+		return len(got) == 1 && got[0] == "team-owner:team-a"
+	})
+
+	del(t, p)
+	del(t, a)
+	eventually(t, "the Profile to be deleted", func() bool { return !get(t, p) })
+	eventually(t, "the AutoTag to be deleted", func() bool { return !get(t, a) })
+}
+
+// tagFilter returns the tag filter of the only severity rule of the profile
+// with the supplied ID.
+func tagFilter(t *testing.T, id string) []string {
+	t.Helper()
+
+	var v struct {
+		SeverityRules []struct {
+			TagFilter []string `json:"tagFilter"`
+		} `json:"severityRules"`
+	}
+	value(t, id, &v)
+	if len(v.SeverityRules) != 1 {
+		t.Fatalf("profile %s: want 1 severity rule, got %d", id, len(v.SeverityRules))
+	}
+	return v.SeverityRules[0].TagFilter
+}
+
+// create the supplied managed resource, referencing the default
+// ProviderConfig.
+func create(t *testing.T, mg resource.Managed) {
+	t.Helper()
+
+	mg.SetProviderConfigReference(&xpv1.Reference{Name: providerConfigName})
+	if err := kube.Create(context.Background(), mg); err != nil {
+		t.Fatalf("Create(%s): %v", mg.GetName(), err)
+	}
+}
+
+// del deletes the supplied managed resource. Its finalizer keeps it around
+// until its settings object is deleted.
+func del(t *testing.T, mg resource.Managed) {
+	t.Helper()
+
+	if err := kube.Delete(context.Background(), mg); err != nil {
+		t.Fatalf("Delete(%s): %v", mg.GetName(), err)
+	}
+}
+
+// value unmarshals the value of the settings object with the supplied ID into
+// v. It fails the test if there is no such object.
+func value(t *testing.T, id string, v any) {
+	t.Helper()
+
+	o, ok := api.Object(id)
+	if !ok {
+		t.Fatalf("settings object %q does not exist", id)
+	}
+	if err := json.Unmarshal(o.Value, v); err != nil {
+		t.Fatalf("json.Unmarshal(...): %v", err)
+	}
+}
+
+// valueName returns the name property of the settings object with the
+// supplied ID.
+func valueName(t *testing.T, id string) string {
+	t.Helper()
+
+	var v struct {
+		Name string `json:"name"`
+	}
+	value(t, id, &v)
+	return v.Name
+}
+
+// setValueName changes the name property of the settings object with the
+// supplied ID, like a user of the Dynatrace UI would.
+func setValueName(t *testing.T, id, name string) {
+	t.Helper()
+
+	v := map[string]any{}
+	value(t, id, &v)
+	v["name"] = name
+	if !api.Update(id, v) {
+		t.Fatalf("settings object %q does not exist", id)
+	}
+}
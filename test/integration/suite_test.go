@@ -0,0 +1,204 @@
+//go:build integration
+
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package integration runs the controllers of the provider against a local
+// Kubernetes API server started by envtest and a fake Settings API. Run it
+// with make test.integration, which downloads the envtest binaries.
+package integration
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/crossplane/provider-dynatrace/apis"
+	apisv1alpha1 "github.com/crossplane/provider-dynatrace/apis/v1alpha1"
+	"github.com/crossplane/provider-dynatrace/internal/clients/settings20/fake"
+	dynatrace "github.com/crossplane/provider-dynatrace/internal/controller"
+)
+
+const (
+	providerConfigName   = "default"
+	credentialsNamespace = "crossplane-system"
+	credentialsName      = "dynatrace-creds"
+	credentialsKey       = "credentials"
+
+	// pollInterval is the poll interval of the controllers, which bounds
+	// how long it takes to correct drift.
+	pollInterval = time.Second
+
+	// timeout exceeds the 30 second grace period during which the managed
+	// reconciler waits for settings objects it created to be observable,
+	// which delays deleting managed resources that were just created.
+	timeout = time.Minute
+	tick    = 100 * time.Millisecond
+)
+
+var (
+	kube client.Client
+	api  *fake.Server
+)
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	os.Exit(run(m))
+}
+
+// run starts the Kubernetes API server, the fake Settings API and the
+// controllers, runs the tests and stops them again.
+func run(m *testing.M) int {
+	zl := zap.New(zap.UseDevMode(testing.Verbose()), zap.WriteTo(os.Stderr))
+	if testing.Verbose() {
+		ctrl.SetLogger(zl)
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "package", "crds")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot start envtest; is KUBEBUILDER_ASSETS set? %v\n", err)
+		return 1
+	}
+	defer env.Stop() //nolint:errcheck // Nothing to do about it.
+
+	api = fake.NewServer()
+	defer api.Close()
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{MetricsBindAddress: "0"})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot create manager: %v\n", err)
+		return 1
+	}
+	if err := apis.AddToScheme(mgr.GetScheme()); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot add APIs to scheme: %v\n", err)
+		return 1
+	}
+	o := controller.Options{
+		Logger:                  logging.NewLogrLogger(zl.WithName("provider-dynatrace")),
+		MaxConcurrentReconciles: 10,
+		PollInterval:            pollInterval,
+		GlobalRateLimiter:       ratelimiter.NewGlobal(10),
+		Features:                &feature.Flags{},
+	}
+	if err := dynatrace.Setup(mgr, o, dynatrace.Selector{"*"}); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot set up controllers: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot start manager: %v\n", err)
+			os.Exit(1)
+		}
+	}()
+
+	kube = mgr.GetClient()
+	if err := createProviderConfig(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot create ProviderConfig: %v\n", err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+// createProviderConfig creates the default ProviderConfig, whose credentials
+// connect to the fake Settings API.
+func createProviderConfig(ctx context.Context) error {
+	for _, o := range []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: credentialsNamespace}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: credentialsNamespace, Name: credentialsName},
+			Data:       map[string][]byte{credentialsKey: api.Credentials()},
+		},
+	} {
+		if err := kube.Create(ctx, o); err != nil {
+			return err
+		}
+	}
+	return kube.Create(ctx, &apisv1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: providerConfigName},
+		Spec: apisv1alpha1.ProviderConfigSpec{Credentials: apisv1alpha1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Namespace: credentialsNamespace, Name: credentialsName},
+				Key:             credentialsKey,
+			}},
+		}},
+	})
+}
+
+// eventually fails the test unless the supplied condition becomes true before
+// the timeout.
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out after %s waiting for %s", timeout, what)
+		}
+		time.Sleep(tick)
+	}
+}
+
+// get refreshes the supplied managed resource. It returns false if the
+// managed resource does not exist.
+func get(t *testing.T, mg resource.Managed) bool {
+	t.Helper()
+
+	err := kube.Get(context.Background(), types.NamespacedName{Name: mg.GetName()}, mg)
+	if kerrors.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		t.Fatalf("Get(%s): %v", mg.GetName(), err)
+	}
+	return true
+}
+
+// ready returns true if the supplied managed resource is ready and synced.
+func ready(t *testing.T, mg resource.Managed) bool {
+	t.Helper()
+
+	return get(t, mg) &&
+		mg.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue &&
+		mg.GetCondition(xpv1.TypeSynced).Status == corev1.ConditionTrue
+}